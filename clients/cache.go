@@ -0,0 +1,237 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultProductsCacheTTL       = 10 * time.Minute
+	DefaultCurrenciesCacheTTL     = time.Hour
+	DefaultTrailingVolumeCacheTTL = time.Hour
+)
+
+/*
+	In-memory cache of GET responses for slow-changing reference data.
+
+	Only the pathnames given a TTL are cached, responses are kept per query string and per API
+	key. Concurrent requests for a resource that is not cached yet share a single HTTP request,
+	the others wait for its response (or for their own context to be done).
+
+	Errors are never cached. A Cache may be shared by several clients.
+*/
+type Cache struct {
+	mu      sync.Mutex
+	ttls    map[string]time.Duration
+	entries map[string]*cacheEntry
+	calls   map[string]*cacheCall
+	now     func() time.Time
+}
+
+type cacheEntry struct {
+	pathname string
+	response *http.Response
+	body     []byte
+	expires  time.Time
+}
+
+/*
+	A request in flight, shared by every caller asking for the same key
+*/
+type cacheCall struct {
+	pathname  string
+	done      chan struct{}
+	forgotten bool
+	response  *http.Response
+	body      []byte
+	err       error
+}
+
+/*
+	Cache for GET /products, GET /currencies and GET /users/self/trailing-volume with the default TTLs
+*/
+func NewCache() *Cache {
+	cache := NewEmptyCache()
+	cache.SetTTL("/products", DefaultProductsCacheTTL)
+	cache.SetTTL("/currencies", DefaultCurrenciesCacheTTL)
+	cache.SetTTL("/users/self/trailing-volume", DefaultTrailingVolumeCacheTTL)
+	return cache
+}
+
+/*
+	Cache without any cached pathname, see SetTTL
+*/
+func NewEmptyCache() *Cache {
+	return &Cache{
+		ttls:    map[string]time.Duration{},
+		entries: map[string]*cacheEntry{},
+		calls:   map[string]*cacheCall{},
+		now:     time.Now,
+	}
+}
+
+/*
+	Cache GET responses of pathname for ttl, a ttl of 0 stops caching it
+*/
+func (c *Cache) SetTTL(pathname string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ttl <= 0 {
+		delete(c.ttls, pathname)
+		c.invalidate(pathname)
+		return
+	}
+	c.ttls[pathname] = ttl
+}
+
+/*
+	How long a response to method pathname is cached, 0 when it is not
+*/
+func (c *Cache) ttl(method, pathname string) time.Duration {
+	if c == nil || method != "GET" {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ttls[pathname]
+}
+
+/*
+	Drop the cached responses of pathname, whatever their query string. A request for pathname
+	already in flight still answers its callers but is not cached.
+*/
+func (c *Cache) Invalidate(pathname string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidate(pathname)
+}
+
+func (c *Cache) invalidate(pathname string) {
+	for key, entry := range c.entries {
+		if entry.pathname == pathname {
+			delete(c.entries, key)
+		}
+	}
+	for key, call := range c.calls {
+		if call.pathname == pathname {
+			call.forgotten = true
+			delete(c.calls, key)
+		}
+	}
+}
+
+/*
+	Drop every cached response
+*/
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]*cacheEntry{}
+	for key, call := range c.calls {
+		call.forgotten = true
+		delete(c.calls, key)
+	}
+}
+
+/*
+	Number of responses currently cached, expired ones included until they are requested again
+*/
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+/*
+	The cached response for key, or the one of fetch which is called at most once at a time per key
+*/
+func (c *Cache) do(ctx context.Context, key, pathname string, ttl time.Duration, fetch func() (*http.Response, []byte, error)) (*http.Response, []byte, error) {
+	for {
+		c.mu.Lock()
+		if entry, ok := c.entries[key]; ok {
+			if c.now().Before(entry.expires) {
+				c.mu.Unlock()
+				return copyResponse(entry.response, entry.body), entry.body, nil
+			}
+			delete(c.entries, key)
+		}
+		call, ok := c.calls[key]
+		if !ok {
+			// Fetch it ourselves, still holding the lock
+			break
+		}
+		c.mu.Unlock()
+		select {
+		case <-call.done:
+			// The fetch ran with the context of the caller that started it, when that caller gave
+			// up the others fetch again rather than fail with its error
+			if isContextError(call.err) && ctx.Err() == nil {
+				continue
+			}
+			return copyResponse(call.response, call.body), call.body, call.err
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+	call := &cacheCall{pathname: pathname, done: make(chan struct{})}
+	c.calls[key] = call
+	c.mu.Unlock()
+
+	call.response, call.body, call.err = fetch()
+
+	c.mu.Lock()
+	if !call.forgotten {
+		delete(c.calls, key)
+		if call.err == nil {
+			c.entries[key] = &cacheEntry{
+				pathname: pathname,
+				response: call.response,
+				body:     call.body,
+				expires:  c.now().Add(ttl),
+			}
+		}
+	}
+	c.mu.Unlock()
+	close(call.done)
+	return copyResponse(call.response, call.body), call.body, call.err
+}
+
+/*
+	Whether err comes from a canceled or expired context, possibly wrapped by the HTTP client
+*/
+func isContextError(err error) bool {
+	if url_error, ok := err.(*url.Error); ok {
+		err = url_error.Err
+	}
+	return err == context.Canceled || err == context.DeadlineExceeded
+}
+
+/*
+	Shallow copy of res with its own header and a fresh body, so callers never share state
+*/
+func copyResponse(res *http.Response, body []byte) *http.Response {
+	if res == nil {
+		return nil
+	}
+	copied := *res
+	copied.Header = http.Header{}
+	for key, values := range res.Header {
+		copied.Header[key] = append([]string{}, values...)
+	}
+	copied.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return &copied
+}
+
+/*
+	Key of a cached response: the API, the path with its query and the API key, since
+	endpoints such as the trailing volume answer differently for every user
+*/
+func (c *Client) cacheKey(partial_url string) string {
+	credentials, _ := c.credentials()
+	return c.URL + partial_url + " " + credentials.Key
+}
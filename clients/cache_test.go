@@ -0,0 +1,243 @@
+package clients
+
+import (
+	"context"
+	"gopkg.in/jarcoal/httpmock.v1"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_Cache_GetCurrencies(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	requests := 0
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/currencies",
+		func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(200, `[{ "id": "BTC", "name": "Bitcoin", "min_size": "0.00000001" }]`), nil
+		},
+	)
+	client := NewMockClient()
+	client.Cache = NewCache()
+	now := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	client.Cache.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		output, err := GetCurrencies(client)
+		if nil != err {
+			t.Fatalf("Expected error to be nil, actual = %v", err)
+		}
+		if len(output) != 1 || output[0].ID != "BTC" {
+			t.Fatalf("Expected the BTC currency, actual = %v", output)
+		}
+	}
+	if requests != 1 {
+		t.Fatalf("Expected 1 request, actual = %v", requests)
+	}
+
+	// Expire the cached response
+	now = now.Add(DefaultCurrenciesCacheTTL)
+	if _, err := GetCurrencies(client); nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("Expected 2 requests after expiry, actual = %v", requests)
+	}
+
+	client.Cache.Invalidate("/currencies")
+	if client.Cache.Len() != 0 {
+		t.Fatalf("Expected an empty cache after Invalidate, actual = %v", client.Cache.Len())
+	}
+	if _, err := GetCurrencies(client); nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if requests != 3 {
+		t.Fatalf("Expected 3 requests after Invalidate, actual = %v", requests)
+	}
+}
+
+func Test_Cache_UncachedPathname(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	requests := 0
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/time",
+		func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(200, `{ "iso": "2015-01-07T23:47:25.201Z", "epoch": 1420674445.201 }`), nil
+		},
+	)
+	client := NewMockClient()
+	client.Cache = NewCache()
+	for i := 0; i < 2; i++ {
+		if _, err := GetTime(client); nil != err {
+			t.Fatalf("Expected error to be nil, actual = %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Fatalf("Expected 2 requests, actual = %v", requests)
+	}
+}
+
+func Test_Cache_ErrorsAreNotCached(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	requests := 0
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/products",
+		func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(500, `{ "message": "Internal Server Error" }`), nil
+		},
+	)
+	client := NewMockClient()
+	client.Cache = NewCache()
+	for i := 0; i < 2; i++ {
+		if _, err := GetProducts(client); !IsServerError(err) {
+			t.Fatalf("Expected a server error, actual = %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Fatalf("Expected 2 requests, actual = %v", requests)
+	}
+}
+
+func Test_Cache_PerAPIKey(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	requests := 0
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/users/self/trailing-volume",
+		func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(200, `[]`), nil
+		},
+	)
+	cache := NewCache()
+	first := NewMockClient()
+	first.Cache = cache
+	second := NewMockClient()
+	second.Key = "b3RoZXIta2V5"
+	second.Cache = cache
+	for _, client := range []*Client{first, second, first, second} {
+		if _, err := GetAccountTrailingVolume(client); nil != err {
+			t.Fatalf("Expected error to be nil, actual = %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Fatalf("Expected 1 request per API key, actual = %v", requests)
+	}
+	cache.Purge()
+	if cache.Len() != 0 {
+		t.Fatalf("Expected an empty cache after Purge, actual = %v", cache.Len())
+	}
+}
+
+func Test_Cache_Singleflight(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	var requests int32
+	release := make(chan struct{})
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/products",
+		func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&requests, 1)
+			<-release
+			return httpmock.NewStringResponse(200, `[{ "id": "BTC-USD", "base_currency": "BTC", "quote_currency": "USD" }]`), nil
+		},
+	)
+	client := NewMockClient()
+	client.Cache = NewCache()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			output, err := GetProducts(client)
+			if nil == err && (len(output) != 1 || output[0].ID != "BTC-USD") {
+				t.Errorf("Expected the BTC-USD product, actual = %v", output)
+			}
+			errs <- err
+		}()
+	}
+	// Let the callers pile up behind the first request
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if nil != err {
+			t.Fatalf("Expected error to be nil, actual = %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Fatalf("Expected 1 request, actual = %v", requests)
+	}
+}
+
+func Test_Cache_Singleflight_canceledCaller(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	var requests int32
+	started := make(chan struct{})
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/products",
+		func(req *http.Request) (*http.Response, error) {
+			if atomic.AddInt32(&requests, 1) == 1 {
+				// Hang until the first caller gives up
+				close(started)
+				<-req.Context().Done()
+				return nil, req.Context().Err()
+			}
+			return httpmock.NewStringResponse(200, `[{ "id": "BTC-USD", "base_currency": "BTC", "quote_currency": "USD" }]`), nil
+		},
+	)
+	client := NewMockClient()
+	client.Cache = NewCache()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := GetProductsContext(ctx, client)
+		first <- err
+	}()
+	<-started
+	second := make(chan error, 1)
+	go func() {
+		output, err := GetProducts(client)
+		if nil == err && (len(output) != 1 || output[0].ID != "BTC-USD") {
+			t.Errorf("Expected the BTC-USD product, actual = %v", output)
+		}
+		second <- err
+	}()
+	// Let the second caller wait behind the first request, then cancel the first caller
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-first; !isContextError(err) {
+		t.Fatalf("Expected the first caller to be canceled, actual = %v", err)
+	}
+	if err := <-second; nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("Expected the second caller to fetch again, actual requests = %v", requests)
+	}
+}
@@ -0,0 +1,298 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+/*
+	Cassettes
+
+	A cassette is a JSON file of recorded request/response pairs. A Client whose HTTPClient is a
+	CassetteRecorder performs real requests and records them, one whose HTTPClient is a
+	CassettePlayer never touches the network and answers from the cassette instead.
+
+	The CB-ACCESS-* request headers are scrubbed before anything is recorded, so cassettes can be
+	committed alongside the tests.
+*/
+type Cassette struct {
+	Interactions []CassetteInteraction `json:"interactions"`
+
+	mu     sync.Mutex
+	played []bool
+}
+
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+type CassetteRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type CassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+/*
+	How UseCassette attaches a cassette to a client
+*/
+type CassetteMode int
+
+const (
+	// Serve requests from an existing cassette file
+	CassetteReplay CassetteMode = iota
+	// Perform real requests and write them to the cassette file
+	CassetteRecord
+	// Replay when the cassette file exists, record otherwise
+	CassetteAuto
+)
+
+/*
+	Returned by a CassettePlayer for a request that has no unplayed recording
+*/
+type CassetteMissError struct {
+	Method string
+	Path   string
+	Query  string
+}
+
+func (e CassetteMissError) Error() string {
+	return fmt.Sprintf("clients: no recorded interaction for %s %s?%s", e.Method, e.Path, e.Query)
+}
+
+/*
+	Read a cassette from a JSON file
+*/
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cassette := &Cassette{}
+	if err := json.Unmarshal(data, cassette); err != nil {
+		return nil, fmt.Errorf("clients: invalid cassette %s: %v", path, err)
+	}
+	return cassette, nil
+}
+
+/*
+	Write the cassette to a JSON file
+*/
+func (c *Cassette) Save(path string) error {
+	c.mu.Lock()
+	data, err := json.MarshalIndent(c, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func (c *Cassette) record(interaction CassetteInteraction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Interactions = append(c.Interactions, interaction)
+}
+
+/*
+	Find the first unplayed interaction matching the method, path and query of req.
+	Identical requests are therefore answered in the order they were recorded.
+*/
+func (c *Cassette) play(req *http.Request) (*CassetteInteraction, error) {
+	method, path, query := req.Method, req.URL.Path, canonicalQuery(req.URL.RawQuery)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.played) != len(c.Interactions) {
+		c.played = make([]bool, len(c.Interactions))
+	}
+	for i := range c.Interactions {
+		recorded := c.Interactions[i].Request
+		if c.played[i] || !strings.EqualFold(recorded.Method, method) || recorded.Path != path || canonicalQuery(recorded.Query) != query {
+			continue
+		}
+		c.played[i] = true
+		return &c.Interactions[i], nil
+	}
+	return nil, CassetteMissError{Method: method, Path: path, Query: query}
+}
+
+/*
+	Rewind the cassette so every interaction can be played again
+*/
+func (c *Cassette) Rewind() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.played = nil
+}
+
+/*
+	Encode a query string with its parameters sorted by key, so that parameter order does not matter
+*/
+func canonicalQuery(raw_query string) string {
+	values, err := url.ParseQuery(raw_query)
+	if err != nil {
+		return raw_query
+	}
+	return values.Encode()
+}
+
+/*
+	Copy header without the CB-ACCESS-* headers
+*/
+func scrubHeader(header http.Header) http.Header {
+	scrubbed := http.Header{}
+	for key, values := range header {
+		if strings.HasPrefix(strings.ToUpper(key), "CB-ACCESS-") {
+			continue
+		}
+		scrubbed[key] = append([]string{}, values...)
+	}
+	return scrubbed
+}
+
+/*
+	Doer performing real requests through Doer, or DefaultHTTPClient when nil,
+	and recording them into Cassette
+*/
+type CassetteRecorder struct {
+	Cassette *Cassette
+	Doer     Doer
+}
+
+func NewCassetteRecorder(cassette *Cassette, doer Doer) *CassetteRecorder {
+	return &CassetteRecorder{Cassette: cassette, Doer: doer}
+}
+
+func (r *CassetteRecorder) Do(req *http.Request) (*http.Response, error) {
+	request_body := []byte{}
+	if req.Body != nil {
+		data, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		request_body = data
+		req.Body = ioutil.NopCloser(bytes.NewReader(data))
+	}
+	doer := r.Doer
+	if doer == nil {
+		doer = DefaultHTTPClient
+	}
+	res, err := doer.Do(req)
+	if err != nil {
+		return res, err
+	}
+	defer res.Body.Close()
+	response_body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return res, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(response_body))
+	r.Cassette.record(CassetteInteraction{
+		Request: CassetteRequest{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  canonicalQuery(req.URL.RawQuery),
+			Header: scrubHeader(req.Header),
+			Body:   string(request_body),
+		},
+		Response: CassetteResponse{
+			StatusCode: res.StatusCode,
+			Header:     res.Header,
+			Body:       string(response_body),
+		},
+	})
+	return res, nil
+}
+
+/*
+	Doer answering requests from Cassette without any network access
+*/
+type CassettePlayer struct {
+	Cassette *Cassette
+}
+
+func NewCassettePlayer(cassette *Cassette) *CassettePlayer {
+	return &CassettePlayer{Cassette: cassette}
+}
+
+func (p *CassettePlayer) Do(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	interaction, err := p.Cassette.play(req)
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{}
+	for key, values := range interaction.Response.Header {
+		header[key] = append([]string{}, values...)
+	}
+	body := []byte(interaction.Response.Body)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+/*
+	Attach the cassette file at path to client according to mode.
+
+	The returned function restores the previous HTTPClient and, when recording, writes the
+	cassette file. It is meant to be deferred in tests:
+
+		stop, err := UseCassette(client, "testdata/products.json", CassetteAuto)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stop()
+*/
+func UseCassette(client *Client, path string, mode CassetteMode) (func() error, error) {
+	if mode == CassetteAuto {
+		mode = CassetteRecord
+		if _, err := os.Stat(path); err == nil {
+			mode = CassetteReplay
+		}
+	}
+	previous := client.HTTPClient
+	restore := func() error {
+		client.HTTPClient = previous
+		return nil
+	}
+	if mode == CassetteReplay {
+		cassette, err := LoadCassette(path)
+		if err != nil {
+			return nil, err
+		}
+		client.HTTPClient = NewCassettePlayer(cassette)
+		return restore, nil
+	}
+	cassette := &Cassette{}
+	client.HTTPClient = NewCassetteRecorder(cassette, previous)
+	return func() error {
+		restore()
+		return cassette.Save(path)
+	}, nil
+}
@@ -0,0 +1,104 @@
+package clients
+
+import (
+	"gopkg.in/jarcoal/httpmock.v1"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Cassette_recordAndReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gdax-cassette")
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "time.json")
+
+	// Record against the mocked API
+	httpmock.Activate()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/time",
+		httpmock.NewStringResponder(200, `{ "iso": "2015-01-07T23:47:25.201Z", "epoch": 1420674445.201 }`),
+	)
+	client := NewMockClient()
+	stop, err := UseCassette(client, path, CassetteAuto)
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if _, ok := client.HTTPClient.(*CassetteRecorder); !ok {
+		t.Fatalf("Expected to record when the cassette does not exist, actual = %T", client.HTTPClient)
+	}
+	if _, err := GetTime(client); err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if err := stop(); err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	httpmock.DeactivateAndReset()
+	data, _ := ioutil.ReadFile(path)
+	if strings.Contains(string(data), "CB-ACCESS") || strings.Contains(string(data), client.Key) {
+		t.Fatalf("Expected the CB-ACCESS-* headers to be scrubbed, actual = %s", data)
+	}
+
+	// Replay without any responder registered
+	client = NewMockClient()
+	stop, err = UseCassette(client, path, CassetteAuto)
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	defer stop()
+	output, err := GetTime(client)
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if output.Epoch != 1420674445.201 {
+		t.Fatalf("Expected output.Epoch = 1420674445.201, actual = %v", output.Epoch)
+	}
+	// Every recording is played once
+	_, err = GetTime(client)
+	if _, ok := err.(CassetteMissError); !ok {
+		t.Fatalf("Expected a CassetteMissError, actual = %v", err)
+	}
+}
+
+func Test_Cassette_replay(t *testing.T) {
+	client := NewMockClient()
+	stop, err := UseCassette(client, "testdata/public_client.cassette.json", CassetteReplay)
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	defer stop()
+
+	products, err := GetProducts(client)
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if len(products) != 1 || products[0].ID != "BTC-USD" {
+		t.Fatalf("Expected the recorded products, actual = %v", products)
+	}
+	// The query is matched regardless of the parameter order
+	start := time.Date(2018, 01, 01, 00, 00, 00, 0, time.UTC)
+	end := time.Date(2018, 01, 01, 01, 00, 00, 0, time.UTC)
+	rates, err := GetProductHistoricRates(client, "BTC-USD", &start, &end, HistoricRateGranularity_1hr)
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if len(rates) != 2 {
+		t.Fatalf("Expected 2 recorded rates, actual = %v", rates)
+	}
+	// Recorded errors are replayed as well
+	_, err = GetProductTicker(client, "BTC-USD")
+	if !IsNotFound(err) {
+		t.Fatalf("Expected the recorded 404, actual = %v", err)
+	}
+	// Anything not recorded is a miss
+	_, err = GetCurrencies(client)
+	if _, ok := err.(CassetteMissError); !ok {
+		t.Fatalf("Expected a CassetteMissError, actual = %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package clients
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
@@ -10,7 +11,6 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
-	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -36,38 +36,50 @@ type Client struct {
 	Secret     string
 	Key        string
 	Passphrase string
+	// Optional source of the credentials, used instead of Key, Secret and Passphrase when set
+	Credentials CredentialProvider
+	// User-Agent header sent with every request, defaults to DefaultUserAgent
+	UserAgent string
+	// Executes the HTTP requests, DefaultHTTPClient is used when nil
+	HTTPClient Doer
+	// Optional client side rate limiter, requests block until allowed when set
+	RateLimiter *RateLimiter
+	// Optional policy for retrying transient failures, requests are not retried when nil
+	RetryPolicy *RetryPolicy
+	// Optional server clock estimate used to timestamp signed requests
+	Clock *ServerClock
+	// Middleware wrapped around every attempt, the first one is the outermost
+	Middleware []Middleware
+	// Optional cache of the GET responses of slow-changing endpoints
+	Cache *Cache
+	// Profile the private requests act on, the default profile of the API key when empty, see ForProfile
+	ProfileID string
 }
 
-type ClientError struct {
-	Message string `json:"message"`
-}
-
-func (e ClientError) Error() string {
-	return e.Message
-}
+const DefaultUserAgent = "Mozilla"
 
+/*
+	Client for the production API, signing with GDAX_PRODUCTION_KEY, GDAX_PRODUCTION_SECRET
+	and GDAX_PRODUCTION_PASSPHRASE from the environment
+*/
 func NewProductionClient() *Client {
-	passphrase := os.Getenv("GDAX_PRODUCTION_PASSPHRASE")
-	secret := os.Getenv("GDAX_PRODUCTION_SECRET")
-	key := os.Getenv("GDAX_PRODUCTION_KEY")
-	return &Client{
-		URL:        "https://api.gdax.com",
-		Secret:     secret,
-		Key:        key,
-		Passphrase: passphrase,
+	client, err := NewClient(WithEnvironment(EnvironmentProduction))
+	if err != nil {
+		panic(err)
 	}
+	return client
 }
 
+/*
+	Client for the sandbox API, signing with GDAX_SANDBOX_KEY, GDAX_SANDBOX_SECRET
+	and GDAX_SANDBOX_PASSPHRASE from the environment
+*/
 func NewSandboxClient() *Client {
-	passphrase := os.Getenv("GDAX_SANDBOX_PASSPHRASE")
-	secret := os.Getenv("GDAX_SANDBOX_SECRET")
-	key := os.Getenv("GDAX_SANDBOX_KEY")
-	return &Client{
-		URL:        "https://api-public.sandbox.gdax.com",
-		Secret:     secret,
-		Key:        key,
-		Passphrase: passphrase,
+	client, err := NewClient(WithEnvironment(EnvironmentSandbox))
+	if err != nil {
+		panic(err)
 	}
+	return client
 }
 
 func NewMockClient() *Client {
@@ -83,15 +95,35 @@ func NewMockClient() *Client {
 }
 
 func (c *Client) Get(pathname string, url_params url.Values, result interface{}) (res *http.Response, err error) {
-	return c.request("GET", pathname, url_params, nil, result)
+	return c.GetContext(context.Background(), pathname, url_params, result)
+}
+
+func (c *Client) GetContext(ctx context.Context, pathname string, url_params url.Values, result interface{}) (res *http.Response, err error) {
+	return c.requestContext(ctx, "GET", pathname, url_params, nil, result)
 }
 
 func (c *Client) Post(pathname string, body_params, result interface{}) (res *http.Response, err error) {
-	return c.request("POST", pathname, nil, body_params, result)
+	return c.PostContext(context.Background(), pathname, body_params, result)
+}
+
+func (c *Client) PostContext(ctx context.Context, pathname string, body_params, result interface{}) (res *http.Response, err error) {
+	return c.requestContext(ctx, "POST", pathname, nil, body_params, result)
 }
 
 func (c *Client) Delete(pathname string, body_params, result interface{}) (res *http.Response, err error) {
-	return c.request("DELETE", pathname, nil, body_params, result)
+	return c.DeleteContext(context.Background(), pathname, body_params, result)
+}
+
+func (c *Client) DeleteContext(ctx context.Context, pathname string, body_params, result interface{}) (res *http.Response, err error) {
+	return c.requestContext(ctx, "DELETE", pathname, nil, body_params, result)
+}
+
+func (c *Client) DeleteWithParams(pathname string, url_params url.Values, result interface{}) (res *http.Response, err error) {
+	return c.DeleteWithParamsContext(context.Background(), pathname, url_params, result)
+}
+
+func (c *Client) DeleteWithParamsContext(ctx context.Context, pathname string, url_params url.Values, result interface{}) (res *http.Response, err error) {
+	return c.requestContext(ctx, "DELETE", pathname, url_params, nil, result)
 }
 
 /*
@@ -117,58 +149,173 @@ func (c *Client) Delete(pathname string, body_params, result interface{}) (res *
 	| 500         | Internal Server Error – We had a problem with our server     |
 */
 func (c *Client) request(method string, pathname string, url_params url.Values, body_params, result interface{}) (*http.Response, error) {
-	// Generate the current timestamp
-	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
-	// Format the url with "/pathname?query=params"
-	partial_url := c.formatUrl(pathname, url_params)
+	return c.requestContext(context.Background(), method, pathname, url_params, body_params, result)
+}
+
+/*
+	Same as request, but the HTTP request is bound to ctx so that cancellation and deadlines
+	abort the call, including while the response body is being read.
+*/
+func (c *Client) requestContext(ctx context.Context, method string, pathname string, url_params url.Values, body_params, result interface{}) (*http.Response, error) {
 	// Encode the message body as a JSON blob
-	body, encoded_data, err := c.encodeBody(body_params)
+	_, encoded_data, err := c.encodeBody(body_params)
 	if err != nil {
 		return nil, err
 	}
-	// Generate the message signature
-	signature, err := c.generateMessageSignature(timestamp, method, partial_url, encoded_data)
+	// Scope private requests to the profile of the client
+	url_params, encoded_data, err = c.applyProfile(pathname, url_params, encoded_data)
 	if err != nil {
 		return nil, err
 	}
+	// Format the url with "/pathname?query=params"
+	partial_url := c.formatUrl(pathname, url_params)
+	fetch := func() (*http.Response, []byte, error) {
+		return c.fetch(ctx, method, pathname, partial_url, encoded_data)
+	}
+	var res *http.Response
+	var body []byte
+	if ttl := c.Cache.ttl(method, pathname); ttl > 0 {
+		res, body, err = c.Cache.do(ctx, c.cacheKey(partial_url), pathname, ttl, fetch)
+	} else {
+		res, body, err = fetch()
+	}
+	if err != nil {
+		return res, err
+	}
+	// Decode the body and return the output
+	err = json.NewDecoder(bytes.NewReader(body)).Decode(result)
+	return res, err
+}
+
+/*
+	Perform the request, retrying it according to the RetryPolicy, and return the response body
+*/
+func (c *Client) fetch(ctx context.Context, method, pathname, partial_url string, encoded_data []byte) (*http.Response, []byte, error) {
+	stats := requestStatsFromContext(ctx)
+	for attempt := 1; ; attempt++ {
+		if stats != nil {
+			stats.Attempts = attempt
+		}
+		call := c.doAttempt(ctx, attempt, method, pathname, partial_url, encoded_data)
+		if call.Err == nil {
+			return call.Response, call.Body, nil
+		}
+		if !c.RetryPolicy.shouldRetry(ctx, method, attempt, call.Response, call.Err) {
+			return call.Response, call.Body, call.Err
+		}
+		if err := sleepContext(ctx, c.RetryPolicy.backoff(attempt, call.Response)); err != nil {
+			return call.Response, call.Body, err
+		}
+	}
+}
+
+/*
+	Sign a single attempt of the request and send it through the middleware chain
+*/
+func (c *Client) doAttempt(ctx context.Context, attempt int, method, pathname, partial_url string, encoded_data []byte) *Call {
+	call := &Call{
+		Method:  method,
+		Path:    pathname,
+		Attempt: attempt,
+	}
+	// Wait for the rate limiter to allow the request, before it gets signed
+	if c.RateLimiter != nil {
+		if err := c.RateLimiter.Wait(ctx, pathname); err != nil {
+			call.Err = err
+			return call
+		}
+	}
+	// Look up the credentials, they may have been rotated since the last request
+	credentials, err := c.credentials()
+	if err != nil {
+		// Public endpoints do not need credentials, send them unsigned
+		if !isPublicPathname(pathname) {
+			call.Err = err
+			return call
+		}
+		credentials = Credentials{}
+	}
+	// Generate the current timestamp
+	timestamp := strconv.FormatInt(c.now().Unix(), 10)
+	// Generate the message signature
+	signature, err := signMessage(credentials.Secret, timestamp, method, partial_url, encoded_data)
+	if err != nil {
+		call.Err = err
+		return call
+	}
 	// Finally create the HTTP request with the given url, body, and headers
-	req, err := http.NewRequest(method, fmt.Sprintf("%s%s", c.URL, partial_url), body)
+	req, err := http.NewRequest(method, fmt.Sprintf("%s%s", c.URL, partial_url), bytes.NewReader(encoded_data))
 	if err != nil {
-		return nil, err
+		call.Err = err
+		return call
 	}
+	req = req.WithContext(ctx)
 	// Add the headers to the request
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("User-Agent", "Mozilla")
+	req.Header.Add("User-Agent", c.userAgent())
 	req.Header.Add("CB-ACCESS-TIMESTAMP", timestamp)
-	if "" != c.Key {
-		req.Header.Add("CB-ACCESS-KEY", c.Key)
+	if "" != credentials.Key {
+		req.Header.Add("CB-ACCESS-KEY", credentials.Key)
 	}
-	if "" != c.Passphrase {
-		req.Header.Add("CB-ACCESS-PASSPHRASE", c.Passphrase)
+	if "" != credentials.Passphrase {
+		req.Header.Add("CB-ACCESS-PASSPHRASE", credentials.Passphrase)
 	}
 	if "" != signature {
 		req.Header.Add("CB-ACCESS-SIGN", signature)
 	}
-	// Execute the HTTP request
-	client := http.Client{}
-	res, err := client.Do(req)
+	call.Request = req
+	// Execute the HTTP request through the middleware chain
+	c.handler()(call)
+	return call
+}
+
+/*
+	Execute the HTTP request of call, read in the response body and decode the error, if any
+*/
+func (c *Client) send(call *Call) {
+	call.Start = time.Now()
+	defer func() {
+		call.Duration = time.Since(call.Start)
+	}()
+	res, err := c.httpClient().Do(call.Request)
+	call.Response = res
 	if err != nil {
-		return res, err
+		call.Err = err
+		return
 	}
 	// Read in the response body
 	defer res.Body.Close()
-	body_data, err := ioutil.ReadAll(res.Body)
-	if nil != err {
-		return res, err
+	call.Body, call.Err = ioutil.ReadAll(res.Body)
+	if call.Err != nil {
+		return
 	}
 	// If the status code is !== 200 then bail now
 	if res.StatusCode != 200 {
-		return res, c.decodeError(body_data)
+		client_error := newClientError(call.Method, call.Path, res, call.Body)
+		client_error.Attempts = call.Attempt
+		call.Err = client_error
 	}
-	// Decode the body and return the output
-	err = json.NewDecoder(bytes.NewReader(body_data)).Decode(result)
-	return res, err
+}
+
+/*
+	The User-Agent header of the requests
+*/
+func (c *Client) userAgent() string {
+	if c.UserAgent != "" {
+		return c.UserAgent
+	}
+	return DefaultUserAgent
+}
+
+/*
+	The HTTP doer used to execute requests
+*/
+func (c *Client) httpClient() Doer {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return DefaultHTTPClient
 }
 
 /*
@@ -213,11 +360,15 @@ func (c *Client) encodeBody(params interface{}) (*bytes.Reader, []byte, error) {
  The method should be UPPER CASE.
 */
 func (c *Client) generateMessageSignature(timestamp, method, partial_url string, encoded_data []byte) (string, error) {
-	if c.Secret == "" {
+	return signMessage(c.Secret, timestamp, method, partial_url, encoded_data)
+}
+
+func signMessage(secret, timestamp, method, partial_url string, encoded_data []byte) (string, error) {
+	if secret == "" {
 		return "", nil
 	}
 	// Decode the secret key
-	key, err := base64.StdEncoding.DecodeString(c.Secret)
+	key, err := base64.StdEncoding.DecodeString(secret)
 	if err != nil {
 		return "", err
 	}
@@ -232,17 +383,3 @@ func (c *Client) generateMessageSignature(timestamp, method, partial_url string,
 	// Encode the signed message into a base64 string
 	return base64.StdEncoding.EncodeToString(signature.Sum(nil)), nil
 }
-
-/*
- Decode the error response
-*/
-func (c *Client) decodeError(body_data []byte) error {
-	client_error := ClientError{}
-	reader := bytes.NewReader(body_data)
-	err := json.NewDecoder(reader).Decode(&client_error)
-	if err != nil {
-		return err
-	}
-	return error(client_error)
-
-}
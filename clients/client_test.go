@@ -1,6 +1,8 @@
 package clients
 
 import (
+	"context"
+	"net/http"
 	"net/url"
 	"testing"
 	"time"
@@ -201,3 +203,54 @@ func Test_Client_decodeError(t *testing.T) {
 		t.Fatalf("Expected to return error, actual = %v", err)
 	}
 }
+
+func Test_Client_GetContext(t *testing.T) {
+	type contextKey string
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	// Mock the time request, capturing the context the request was sent with
+	var actual interface{}
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/time",
+		func(req *http.Request) (*http.Response, error) {
+			actual = req.Context().Value(contextKey("request-id"))
+			return httpmock.NewStringResponse(200, `{ "iso": "2015-01-07T23:47:25.201Z", "epoch": 1420674445.201 }`), nil
+		},
+	)
+	client := NewMockClient()
+	ctx := context.WithValue(context.Background(), contextKey("request-id"), "abc123")
+	output := &GdaxTimeResponse{}
+	_, err := client.GetContext(ctx, "/time", url.Values{}, output)
+	if nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if actual != "abc123" {
+		t.Fatalf("Expected the request context to be propagated, actual = %v", actual)
+	}
+}
+
+func Test_Client_GetContext_canceled(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/products/BTC-USD/book",
+		func(req *http.Request) (*http.Response, error) {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		},
+	)
+	client := NewMockClient()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	output, err := GetProductOrderBookLevel2Context(ctx, client, "BTC-USD")
+	if nil == err {
+		t.Fatalf("Expected an error, actual = %v", err)
+	}
+	if nil != output {
+		t.Fatalf("Expected output to be nil, actual = %v", output)
+	}
+}
@@ -0,0 +1,113 @@
+package clients
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+/*
+	Estimate of the offset between the local clock and the API server clock.
+
+	Signed requests are rejected when CB-ACCESS-TIMESTAMP is more than 30 seconds away from
+	the server time, so a Client with a ServerClock signs with the local time corrected by the
+	measured offset. It is safe for concurrent use.
+*/
+type ServerClock struct {
+	offset int64
+}
+
+func NewServerClock() *ServerClock {
+	return &ServerClock{}
+}
+
+/*
+	The estimated server time
+*/
+func (s *ServerClock) Now() time.Time {
+	return time.Now().Add(s.Offset())
+}
+
+/*
+	How far the server clock is ahead of the local clock, negative when it is behind
+*/
+func (s *ServerClock) Offset() time.Duration {
+	return time.Duration(atomic.LoadInt64(&s.offset))
+}
+
+func (s *ServerClock) SetOffset(offset time.Duration) {
+	atomic.StoreInt64(&s.offset, int64(offset))
+}
+
+/*
+	Measure the offset with a call to GET /time.
+
+	The server time is assumed to be read halfway through the round trip, so the error of the
+	estimate is at most half the round trip time. The round trip is the HTTP exchange of the
+	successful attempt, rate limiter waits and retry backoffs are left out. The measured offset
+	is returned and stored.
+*/
+func (s *ServerClock) Calibrate(ctx context.Context, client *Client) (time.Duration, error) {
+	var sent_at time.Time
+	var round_trip time.Duration
+	measured := *client
+	measured.Middleware = append([]Middleware{}, client.Middleware...)
+	measured.Use(func(next Handler) Handler {
+		return func(call *Call) {
+			next(call)
+			if call.Err == nil {
+				sent_at, round_trip = call.Start, call.Duration
+			}
+		}
+	})
+	output, err := GetTimeContext(ctx, &measured)
+	if err != nil {
+		return 0, err
+	}
+	local := sent_at.Add(round_trip / 2)
+	offset := epochToTime(output.Epoch).Sub(local)
+	s.SetOffset(offset)
+	return offset, nil
+}
+
+/*
+	Calibrate now and then every interval until ctx is done.
+
+	A failed calibration keeps the previous offset and is reported to on_error when not nil.
+	Typically run in its own goroutine:
+
+		go clock.Sync(ctx, client, 10*time.Minute, nil)
+*/
+func (s *ServerClock) Sync(ctx context.Context, client *Client, interval time.Duration, on_error func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.Calibrate(ctx, client); err != nil && on_error != nil && ctx.Err() == nil {
+			on_error(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+/*
+	Convert decimal seconds since the Unix Epoch into a time
+*/
+func epochToTime(epoch float64) time.Time {
+	seconds, fraction := math.Modf(epoch)
+	return time.Unix(int64(seconds), int64(fraction*1e9))
+}
+
+/*
+	The time used to sign requests
+*/
+func (c *Client) now() time.Time {
+	if c.Clock != nil {
+		return c.Clock.Now()
+	}
+	return time.Now()
+}
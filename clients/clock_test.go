@@ -0,0 +1,121 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"gopkg.in/jarcoal/httpmock.v1"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func Test_ServerClock_Calibrate(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	// The server clock is 90 seconds ahead of ours
+	skew := 90 * time.Second
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/time",
+		func(req *http.Request) (*http.Response, error) {
+			server_time := time.Now().Add(skew)
+			epoch := float64(server_time.UnixNano()) / 1e9
+			return httpmock.NewStringResponse(200, fmt.Sprintf(`{ "iso": "%s", "epoch": %f }`, server_time.Format(time.RFC3339Nano), epoch)), nil
+		},
+	)
+	clock := NewServerClock()
+	client := NewMockClient()
+	client.Clock = clock
+	offset, err := clock.Calibrate(context.Background(), client)
+	if nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if offset < skew-time.Second || offset > skew+time.Second {
+		t.Fatalf("Expected an offset close to %v, actual = %v", skew, offset)
+	}
+	if clock.Offset() != offset {
+		t.Fatalf("Expected the offset to be stored, actual = %v", clock.Offset())
+	}
+	// Signed requests use the corrected time
+	var timestamp string
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/users/self/trailing-volume",
+		func(req *http.Request) (*http.Response, error) {
+			timestamp = req.Header.Get("CB-ACCESS-TIMESTAMP")
+			return httpmock.NewStringResponse(200, `[]`), nil
+		},
+	)
+	if _, err := GetAccountTrailingVolume(client); nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	seconds, _ := strconv.ParseInt(timestamp, 10, 64)
+	if drift := time.Unix(seconds, 0).Sub(time.Now().Add(skew)); drift < -2*time.Second || drift > 2*time.Second {
+		t.Fatalf("Expected CB-ACCESS-TIMESTAMP to follow the server clock, actual drift = %v", drift)
+	}
+}
+
+func Test_ServerClock_Calibrate_waits(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	skew := 90 * time.Second
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/time",
+		func(req *http.Request) (*http.Response, error) {
+			server_time := time.Now().Add(skew)
+			epoch := float64(server_time.UnixNano()) / 1e9
+			return httpmock.NewStringResponse(200, fmt.Sprintf(`{ "iso": "%s", "epoch": %f }`, server_time.Format(time.RFC3339Nano), epoch)), nil
+		},
+	)
+	clock := NewServerClock()
+	client := NewMockClient()
+	// Hold the call back before it is sent, as a busy rate limiter would
+	client.Use(func(next Handler) Handler {
+		return func(call *Call) {
+			time.Sleep(400 * time.Millisecond)
+			next(call)
+		}
+	})
+	offset, err := clock.Calibrate(context.Background(), client)
+	if nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if offset < skew-100*time.Millisecond || offset > skew+100*time.Millisecond {
+		t.Fatalf("Expected the wait to be left out of the round trip, actual offset = %v", offset)
+	}
+	if len(client.Middleware) != 1 {
+		t.Fatalf("Expected the middleware of the client to be kept as is, actual = %v", len(client.Middleware))
+	}
+}
+
+func Test_ServerClock_Calibrate_error(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/time",
+		httpmock.NewStringResponder(500, `{ "message": "Internal Server Error" }`),
+	)
+	clock := NewServerClock()
+	clock.SetOffset(time.Minute)
+	_, err := clock.Calibrate(context.Background(), NewMockClient())
+	if nil == err {
+		t.Fatalf("Expected to return error, actual = %v", err)
+	}
+	if clock.Offset() != time.Minute {
+		t.Fatalf("Expected the previous offset to be kept, actual = %v", clock.Offset())
+	}
+}
+
+func Test_epochToTime(t *testing.T) {
+	actual := epochToTime(1420674445.201)
+	expected := time.Date(2015, 01, 07, 23, 47, 25, 201000000, time.UTC)
+	if diff := actual.Sub(expected); diff < -time.Microsecond || diff > time.Microsecond {
+		t.Fatalf("Expected %v, actual = %v", expected, actual.UTC())
+	}
+}
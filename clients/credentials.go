@@ -0,0 +1,280 @@
+package clients
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+	API key, secret and passphrase used to sign private requests
+*/
+type Credentials struct {
+	Key        string `json:"key"`
+	Secret     string `json:"secret"`
+	Passphrase string `json:"passphrase"`
+}
+
+/*
+	Whether the key, secret and passphrase are all set, a request cannot be signed otherwise
+*/
+func (c Credentials) complete() bool {
+	return c.Key != "" && c.Secret != "" && c.Passphrase != ""
+}
+
+/*
+	Source of the credentials of a Client.
+
+	The provider is consulted for every request, so a provider that reloads its source lets
+	keys be rotated without restarting the process.
+*/
+type CredentialProvider interface {
+	Credentials() (Credentials, error)
+}
+
+/*
+	Returned by a provider that has no credentials to offer. Requests made without credentials
+	are sent unsigned, which is enough for the public endpoints.
+*/
+var ErrCredentialsNotFound = errors.New("clients: credentials not found")
+
+/*
+	Provider returning fixed credentials
+*/
+type StaticCredentialProvider Credentials
+
+func (p StaticCredentialProvider) Credentials() (Credentials, error) {
+	return Credentials(p), nil
+}
+
+/*
+	Provider reading <Prefix>_KEY, <Prefix>_SECRET and <Prefix>_PASSPHRASE from the environment,
+	e.g. GDAX_SANDBOX_KEY, GDAX_SANDBOX_SECRET and GDAX_SANDBOX_PASSPHRASE for the "GDAX_SANDBOX" prefix.
+	Incomplete credentials fail the private requests, public requests are still sent unsigned.
+*/
+type EnvCredentialProvider struct {
+	Prefix string
+}
+
+func (p EnvCredentialProvider) Credentials() (Credentials, error) {
+	credentials := Credentials{
+		Key:        os.Getenv(p.Prefix + "_KEY"),
+		Secret:     os.Getenv(p.Prefix + "_SECRET"),
+		Passphrase: os.Getenv(p.Prefix + "_PASSPHRASE"),
+	}
+	if credentials == (Credentials{}) {
+		return Credentials{}, ErrCredentialsNotFound
+	}
+	if !credentials.complete() {
+		return Credentials{}, fmt.Errorf("clients: incomplete credentials in %s_KEY, %s_SECRET and %s_PASSPHRASE", p.Prefix, p.Prefix, p.Prefix)
+	}
+	return credentials, nil
+}
+
+/*
+	Provider reading one profile of a JSON or YAML credentials file, picked by the extension
+	(.json, .yaml or .yml). The file maps profile names to credentials:
+
+		{
+			"default": { "key": "...", "secret": "...", "passphrase": "..." },
+			"trading": { "key": "...", "secret": "...", "passphrase": "..." }
+		}
+
+	or in YAML:
+
+		default:
+		  key: ...
+		  secret: ...
+		  passphrase: ...
+
+	The file must not be accessible by group or others (e.g. mode 0600). It is read again
+	whenever its modification time changes, so the credentials can be rotated in place.
+*/
+type FileCredentialProvider struct {
+	Path string
+	// Profile to read, "default" when empty
+	Profile string
+
+	mu       sync.Mutex
+	mod_time time.Time
+	profiles map[string]Credentials
+}
+
+func NewFileCredentialProvider(path, profile string) *FileCredentialProvider {
+	return &FileCredentialProvider{Path: path, Profile: profile}
+}
+
+func (p *FileCredentialProvider) Credentials() (Credentials, error) {
+	profiles, err := p.load()
+	if err != nil {
+		return Credentials{}, err
+	}
+	profile := p.Profile
+	if profile == "" {
+		profile = "default"
+	}
+	credentials, ok := profiles[profile]
+	if !ok {
+		return Credentials{}, ErrCredentialsNotFound
+	}
+	if !credentials.complete() {
+		return Credentials{}, fmt.Errorf("clients: incomplete credentials in profile %q of %s", profile, p.Path)
+	}
+	return credentials, nil
+}
+
+/*
+	The profiles of the file, read again when it was modified since the last call
+*/
+func (p *FileCredentialProvider) load() (map[string]Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	info, err := os.Stat(p.Path)
+	if os.IsNotExist(err) {
+		return nil, ErrCredentialsNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("clients: credentials file %s is accessible by others (mode %v), expected 0600", p.Path, info.Mode().Perm())
+	}
+	if p.profiles != nil && info.ModTime().Equal(p.mod_time) {
+		return p.profiles, nil
+	}
+	data, err := ioutil.ReadFile(p.Path)
+	if err != nil {
+		return nil, err
+	}
+	profiles := map[string]Credentials{}
+	switch strings.ToLower(filepath.Ext(p.Path)) {
+	case ".json":
+		err = json.Unmarshal(data, &profiles)
+	case ".yaml", ".yml":
+		profiles, err = parseCredentialsYAML(data)
+	default:
+		err = errors.New("unsupported extension, expected .json, .yaml or .yml")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("clients: invalid credentials file %s: %v", p.Path, err)
+	}
+	p.profiles = profiles
+	p.mod_time = info.ModTime()
+	return profiles, nil
+}
+
+/*
+	Parse the two level YAML mapping of a credentials file: profile names, each holding
+	indented key, secret and passphrase scalars. Comments and quoted values are supported.
+*/
+func parseCredentialsYAML(data []byte) (map[string]Credentials, error) {
+	profiles := map[string]Credentials{}
+	profile := ""
+	for i, raw_line := range strings.Split(string(data), "\n") {
+		line := strings.TrimRight(stripYAMLComment(raw_line), " \t\r")
+		if strings.TrimSpace(line) == "" || line == "---" {
+			continue
+		}
+		name, value, ok := splitYAMLPair(line)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"name: value\"", i+1)
+		}
+		indented := line[0] == ' ' || line[0] == '\t'
+		if !indented {
+			if value != "" {
+				return nil, fmt.Errorf("line %d: expected a profile name", i+1)
+			}
+			profile = name
+			profiles[profile] = Credentials{}
+			continue
+		}
+		if profile == "" {
+			return nil, fmt.Errorf("line %d: field outside of a profile", i+1)
+		}
+		credentials := profiles[profile]
+		switch name {
+		case "key":
+			credentials.Key = value
+		case "secret":
+			credentials.Secret = value
+		case "passphrase":
+			credentials.Passphrase = value
+		default:
+			return nil, fmt.Errorf("line %d: unknown field %q", i+1, name)
+		}
+		profiles[profile] = credentials
+	}
+	return profiles, nil
+}
+
+func splitYAMLPair(line string) (string, string, bool) {
+	index := strings.Index(line, ":")
+	if index < 0 {
+		return "", "", false
+	}
+	name := strings.TrimSpace(line[:index])
+	value := strings.TrimSpace(line[index+1:])
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+	return name, value, name != ""
+}
+
+/*
+	Remove a trailing "# comment", unless the # is inside a quoted value
+*/
+func stripYAMLComment(line string) string {
+	quote := byte(0)
+	for i := 0; i < len(line); i++ {
+		switch {
+		case quote != 0:
+			if line[i] == quote {
+				quote = 0
+			}
+		case line[i] == '"' || line[i] == '\'':
+			quote = line[i]
+		case line[i] == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+/*
+	Provider trying each provider in turn and returning the first credentials found.
+	Providers answering ErrCredentialsNotFound are skipped, any other error is returned.
+*/
+type ChainCredentialProvider []CredentialProvider
+
+func (p ChainCredentialProvider) Credentials() (Credentials, error) {
+	for _, provider := range p {
+		credentials, err := provider.Credentials()
+		if err == ErrCredentialsNotFound {
+			continue
+		}
+		return credentials, err
+	}
+	return Credentials{}, ErrCredentialsNotFound
+}
+
+/*
+	The credentials to sign the next request with: those of the provider when the client
+	has one, the Key, Secret and Passphrase fields otherwise
+*/
+func (c *Client) credentials() (Credentials, error) {
+	if c.Credentials == nil {
+		return Credentials{Key: c.Key, Secret: c.Secret, Passphrase: c.Passphrase}, nil
+	}
+	credentials, err := c.Credentials.Credentials()
+	if err == ErrCredentialsNotFound {
+		return Credentials{}, nil
+	}
+	return credentials, err
+}
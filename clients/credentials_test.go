@@ -0,0 +1,195 @@
+package clients
+
+import (
+	"gopkg.in/jarcoal/httpmock.v1"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_EnvCredentialProvider(t *testing.T) {
+	os.Setenv("GDAX_TEST_KEY", "key")
+	os.Setenv("GDAX_TEST_SECRET", "c2VjcmV0")
+	os.Setenv("GDAX_TEST_PASSPHRASE", "passphrase")
+	defer os.Unsetenv("GDAX_TEST_KEY")
+	defer os.Unsetenv("GDAX_TEST_SECRET")
+	defer os.Unsetenv("GDAX_TEST_PASSPHRASE")
+	credentials, err := EnvCredentialProvider{Prefix: "GDAX_TEST"}.Credentials()
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	expected := Credentials{Key: "key", Secret: "c2VjcmV0", Passphrase: "passphrase"}
+	if credentials != expected {
+		t.Fatalf("Expected %v, actual = %v", expected, credentials)
+	}
+	if _, err := (EnvCredentialProvider{Prefix: "GDAX_MISSING"}).Credentials(); err != ErrCredentialsNotFound {
+		t.Fatalf("Expected ErrCredentialsNotFound, actual = %v", err)
+	}
+	os.Unsetenv("GDAX_TEST_PASSPHRASE")
+	if _, err := (EnvCredentialProvider{Prefix: "GDAX_TEST"}).Credentials(); err == nil || err == ErrCredentialsNotFound {
+		t.Fatalf("Expected incomplete credentials to be an error, actual = %v", err)
+	}
+}
+
+func writeCredentialsFile(t *testing.T, dir, name, content string, mode os.FileMode) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	return path
+}
+
+func Test_FileCredentialProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gdax-credentials")
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	json_path := writeCredentialsFile(t, dir, "credentials.json", `{
+		"default": { "key": "json-key", "secret": "c2VjcmV0", "passphrase": "json-passphrase" },
+		"trading": { "key": "trading-key", "secret": "c2VjcmV0", "passphrase": "trading-passphrase" },
+		"partial": { "key": "partial-key" }
+	}`, 0600)
+	credentials, err := NewFileCredentialProvider(json_path, "").Credentials()
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if credentials.Key != "json-key" {
+		t.Fatalf("Expected the default profile, actual = %v", credentials)
+	}
+	credentials, err = NewFileCredentialProvider(json_path, "trading").Credentials()
+	if err != nil || credentials.Passphrase != "trading-passphrase" {
+		t.Fatalf("Expected the trading profile, actual = %v %v", credentials, err)
+	}
+	if _, err := NewFileCredentialProvider(json_path, "missing").Credentials(); err != ErrCredentialsNotFound {
+		t.Fatalf("Expected ErrCredentialsNotFound, actual = %v", err)
+	}
+	if _, err := NewFileCredentialProvider(json_path, "partial").Credentials(); err == nil || err == ErrCredentialsNotFound {
+		t.Fatalf("Expected incomplete credentials to be an error, actual = %v", err)
+	}
+
+	yaml_path := writeCredentialsFile(t, dir, "credentials.yaml", `
+# GDAX API keys
+default:
+  key: yaml-key
+  secret: "c2VjcmV0"
+  passphrase: 'pass # phrase' # quoted hash
+`, 0600)
+	credentials, err = NewFileCredentialProvider(yaml_path, "default").Credentials()
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	expected := Credentials{Key: "yaml-key", Secret: "c2VjcmV0", Passphrase: "pass # phrase"}
+	if credentials != expected {
+		t.Fatalf("Expected %v, actual = %v", expected, credentials)
+	}
+
+	insecure_path := writeCredentialsFile(t, dir, "insecure.json", `{}`, 0644)
+	if _, err := NewFileCredentialProvider(insecure_path, "").Credentials(); err == nil || err == ErrCredentialsNotFound {
+		t.Fatalf("Expected a permission error, actual = %v", err)
+	}
+}
+
+func Test_FileCredentialProvider_rotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gdax-credentials")
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := writeCredentialsFile(t, dir, "credentials.json", `{ "default": { "key": "old-key", "secret": "c2VjcmV0", "passphrase": "passphrase" } }`, 0600)
+	provider := NewFileCredentialProvider(path, "")
+	if credentials, _ := provider.Credentials(); credentials.Key != "old-key" {
+		t.Fatalf("Expected old-key, actual = %v", credentials)
+	}
+	writeCredentialsFile(t, dir, "credentials.json", `{ "default": { "key": "new-key", "secret": "c2VjcmV0", "passphrase": "passphrase" } }`, 0600)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(path, later, later)
+	if credentials, _ := provider.Credentials(); credentials.Key != "new-key" {
+		t.Fatalf("Expected the rotated key, actual = %v", credentials)
+	}
+}
+
+func Test_ChainCredentialProvider(t *testing.T) {
+	chain := ChainCredentialProvider{
+		EnvCredentialProvider{Prefix: "GDAX_MISSING"},
+		StaticCredentialProvider{Key: "static-key"},
+	}
+	credentials, err := chain.Credentials()
+	if err != nil || credentials.Key != "static-key" {
+		t.Fatalf("Expected the static credentials, actual = %v %v", credentials, err)
+	}
+	if _, err := (ChainCredentialProvider{}).Credentials(); err != ErrCredentialsNotFound {
+		t.Fatalf("Expected ErrCredentialsNotFound, actual = %v", err)
+	}
+}
+
+func Test_Client_Credentials(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	keys := []string{}
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/time",
+		func(req *http.Request) (*http.Response, error) {
+			keys = append(keys, req.Header.Get("CB-ACCESS-KEY")+"/"+req.Header.Get("CB-ACCESS-PASSPHRASE"))
+			if req.Header.Get("CB-ACCESS-KEY") != "" && req.Header.Get("CB-ACCESS-SIGN") == "" {
+				t.Fatalf("Expected the request to be signed")
+			}
+			return httpmock.NewStringResponse(200, `{ "iso": "2015-01-07T23:47:25.201Z", "epoch": 1420674445.201 }`), nil
+		},
+	)
+	client := NewMockClient()
+	client.Credentials = StaticCredentialProvider{Key: "provided-key", Secret: "c2VjcmV0", Passphrase: "provided-passphrase"}
+	if _, err := GetTime(client); err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	// Without credentials the request is sent unsigned
+	client.Credentials = ChainCredentialProvider{}
+	if _, err := GetTime(client); err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "provided-key/provided-passphrase" || keys[1] != "/" {
+		t.Fatalf("Expected the provided credentials then none, actual = %v", keys)
+	}
+}
+
+func Test_Client_Credentials_incomplete(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/time",
+		func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("CB-ACCESS-KEY") != "" || req.Header.Get("CB-ACCESS-SIGN") != "" {
+				t.Fatalf("Expected the request to be unsigned")
+			}
+			return httpmock.NewStringResponse(200, `{ "iso": "2015-01-07T23:47:25.201Z", "epoch": 1420674445.201 }`), nil
+		},
+	)
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/accounts",
+		httpmock.NewStringResponder(200, `[]`),
+	)
+	os.Setenv("GDAX_PARTIAL_KEY", "key")
+	defer os.Unsetenv("GDAX_PARTIAL_KEY")
+	client := NewMockClient()
+	client.Credentials = EnvCredentialProvider{Prefix: "GDAX_PARTIAL"}
+	// Public requests are sent unsigned
+	if _, err := GetTime(client); err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	// Private requests fail
+	if _, err := GetAccounts(client); err == nil {
+		t.Fatalf("Expected incomplete credentials to be an error")
+	}
+}
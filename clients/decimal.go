@@ -0,0 +1,387 @@
+package clients
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+/*
+	Arbitrary precision decimal number, used for every price, size, fee and volume.
+
+	GDAX sends amounts as strings such as "0.00000001" precisely so that they are not rounded
+	by a float64. A Decimal keeps the exact value along with the number of digits after the
+	decimal point, so "10000.00" is printed back as "10000.00".
+
+	The zero value is 0. Decimals are immutable, every operation returns a new value.
+*/
+type Decimal struct {
+	// The value is unscaled / 10^scale, a nil unscaled means 0
+	unscaled *big.Int
+	scale    int32
+}
+
+/*
+	Number of digits after the decimal point kept by Div
+*/
+var DivisionPrecision int32 = 16
+
+var (
+	bigZero = big.NewInt(0)
+	bigTen  = big.NewInt(10)
+)
+
+/*
+	The decimal unscaled / 10^scale, e.g. NewDecimal(12345, 2) is 123.45 and NewDecimal(5, -2) is 500
+*/
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	return newDecimal(big.NewInt(unscaled), scale)
+}
+
+func NewDecimalFromInt(value int64) Decimal {
+	return NewDecimal(value, 0)
+}
+
+/*
+	The decimal closest to value with as few digits as possible, e.g. 0.1 is 0.1 and not
+	0.1000000000000000055511151231257827
+
+	Panics when value is NaN or infinite, which no decimal represents.
+*/
+func NewDecimalFromFloat(value float64) Decimal {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		panic(fmt.Sprintf("clients: cannot convert %v to a decimal", value))
+	}
+	d, err := NewDecimalFromString(strconv.FormatFloat(value, 'f', -1, 64))
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+/*
+	Largest exponent accepted by NewDecimalFromString, in absolute value. Larger exponents would
+	make the parser allocate and compute huge powers of ten.
+*/
+const MaxDecimalExponent = 1000
+
+/*
+	Parse a decimal such as "123.45", "-0.00000001", ".5" or "1e-8"
+*/
+func NewDecimalFromString(value string) (Decimal, error) {
+	s := strings.TrimSpace(value)
+	exponent := int64(0)
+	if index := strings.IndexAny(s, "eE"); index >= 0 {
+		e, err := strconv.ParseInt(s[index+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("clients: invalid decimal %q", value)
+		}
+		if e > MaxDecimalExponent || e < -MaxDecimalExponent {
+			return Decimal{}, fmt.Errorf("clients: invalid decimal %q, exponent out of range", value)
+		}
+		exponent = e
+		s = s[:index]
+	}
+	scale := int64(0)
+	if index := strings.IndexByte(s, '.'); index >= 0 {
+		scale = int64(len(s) - index - 1)
+		s = s[:index] + s[index+1:]
+	}
+	digits := strings.TrimLeft(s, "+-")
+	if digits == "" || len(s)-len(digits) > 1 || strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return Decimal{}, fmt.Errorf("clients: invalid decimal %q", value)
+	}
+	unscaled, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("clients: invalid decimal %q", value)
+	}
+	scale -= exponent
+	if scale > math.MaxInt32 {
+		return Decimal{}, fmt.Errorf("clients: invalid decimal %q, too many digits", value)
+	}
+	if scale < 0 {
+		unscaled.Mul(unscaled, pow10(-scale))
+		scale = 0
+	}
+	return newDecimal(unscaled, int32(scale)), nil
+}
+
+/*
+	Same as NewDecimalFromString but panics on invalid input, for constants
+*/
+func MustDecimal(value string) Decimal {
+	d, err := NewDecimalFromString(value)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+/*
+	A negative scale is folded into unscaled, so that the scale is always the number of digits
+	after the decimal point
+*/
+func newDecimal(unscaled *big.Int, scale int32) Decimal {
+	if scale < 0 {
+		unscaled = new(big.Int).Mul(unscaled, pow10(-int64(scale)))
+		scale = 0
+	}
+	return Decimal{unscaled: unscaled, scale: scale}
+}
+
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(n), nil)
+}
+
+func (d Decimal) value() *big.Int {
+	if d.unscaled == nil {
+		return bigZero
+	}
+	return d.unscaled
+}
+
+/*
+	The same value with scale digits after the decimal point, scale must not be smaller than d.scale
+*/
+func (d Decimal) rescale(scale int32) *big.Int {
+	if scale == d.scale {
+		return d.value()
+	}
+	return new(big.Int).Mul(d.value(), pow10(int64(scale-d.scale)))
+}
+
+func maxScale(a, b Decimal) int32 {
+	if a.scale > b.scale {
+		return a.scale
+	}
+	return b.scale
+}
+
+/*
+	Number of digits after the decimal point
+*/
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+func (d Decimal) Add(other Decimal) Decimal {
+	scale := maxScale(d, other)
+	return newDecimal(new(big.Int).Add(d.rescale(scale), other.rescale(scale)), scale)
+}
+
+func (d Decimal) Sub(other Decimal) Decimal {
+	scale := maxScale(d, other)
+	return newDecimal(new(big.Int).Sub(d.rescale(scale), other.rescale(scale)), scale)
+}
+
+func (d Decimal) Mul(other Decimal) Decimal {
+	return newDecimal(new(big.Int).Mul(d.value(), other.value()), d.scale+other.scale)
+}
+
+/*
+	d / other rounded to DivisionPrecision digits. Panics when other is zero.
+*/
+func (d Decimal) Div(other Decimal) Decimal {
+	return d.DivRound(other, DivisionPrecision)
+}
+
+/*
+	d / other rounded half away from zero to places digits. Panics when other is zero.
+*/
+func (d Decimal) DivRound(other Decimal, places int32) Decimal {
+	if other.Sign() == 0 {
+		panic("clients: decimal division by zero")
+	}
+	if places < 0 {
+		places = 0
+	}
+	// d / other * 10^places = d.unscaled * 10^(other.scale + places - d.scale) / other.unscaled
+	numerator := new(big.Int).Set(d.value())
+	denominator := new(big.Int).Set(other.value())
+	exponent := int64(other.scale) + int64(places) - int64(d.scale)
+	if exponent >= 0 {
+		numerator.Mul(numerator, pow10(exponent))
+	} else {
+		denominator.Mul(denominator, pow10(-exponent))
+	}
+	return newDecimal(quoRoundHalfUp(numerator, denominator), places)
+}
+
+/*
+	numerator / denominator rounded half away from zero
+*/
+func quoRoundHalfUp(numerator, denominator *big.Int) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient
+	}
+	twice := new(big.Int).Abs(remainder)
+	twice.Lsh(twice, 1)
+	if twice.Cmp(new(big.Int).Abs(denominator)) >= 0 {
+		if numerator.Sign()*denominator.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return quotient
+}
+
+func (d Decimal) Neg() Decimal {
+	return newDecimal(new(big.Int).Neg(d.value()), d.scale)
+}
+
+func (d Decimal) Abs() Decimal {
+	return newDecimal(new(big.Int).Abs(d.value()), d.scale)
+}
+
+/*
+	-1 if d < 0, 0 if d == 0 and +1 if d > 0
+*/
+func (d Decimal) Sign() int {
+	return d.value().Sign()
+}
+
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+/*
+	-1 if d < other, 0 if d == other and +1 if d > other
+*/
+func (d Decimal) Cmp(other Decimal) int {
+	scale := maxScale(d, other)
+	return d.rescale(scale).Cmp(other.rescale(scale))
+}
+
+/*
+	Whether both decimals have the same value, regardless of their scale (1.0 equals 1.00)
+*/
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+func (d Decimal) GreaterThan(other Decimal) bool {
+	return d.Cmp(other) > 0
+}
+
+func (d Decimal) LessThan(other Decimal) bool {
+	return d.Cmp(other) < 0
+}
+
+/*
+	d rounded half away from zero to places digits after the decimal point,
+	e.g. 1.005 rounded to 2 places is 1.01 and -1.005 is -1.01
+*/
+func (d Decimal) Round(places int32) Decimal {
+	if places >= d.scale {
+		return d
+	}
+	return d.DivRound(NewDecimalFromInt(1), places)
+}
+
+/*
+	d truncated towards zero to places digits after the decimal point
+*/
+func (d Decimal) Truncate(places int32) Decimal {
+	if places < 0 {
+		places = 0
+	}
+	if places >= d.scale {
+		return d
+	}
+	quotient := new(big.Int).Quo(d.value(), pow10(int64(d.scale-places)))
+	return newDecimal(quotient, places)
+}
+
+/*
+	The largest multiple of increment that is not greater than d, e.g. a price floored to the
+	quote_increment of a product. Panics when increment is zero.
+*/
+func (d Decimal) FloorToIncrement(increment Decimal) Decimal {
+	scale := maxScale(d, increment)
+	step := increment.rescale(scale)
+	if step.Sign() == 0 {
+		panic("clients: decimal increment is zero")
+	}
+	value := d.rescale(scale)
+	multiple := new(big.Int).Div(value, new(big.Int).Abs(step))
+	return newDecimal(multiple.Mul(multiple, new(big.Int).Abs(step)), scale).Truncate(increment.scale)
+}
+
+/*
+	The nearest float64, which may lose precision
+*/
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+/*
+	The decimal representation with all of its digits, e.g. "10000.00"
+*/
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.value()).String()
+	sign := ""
+	if d.Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale <= 0 {
+		return sign + digits
+	}
+	if len(digits) <= int(d.scale) {
+		digits = strings.Repeat("0", int(d.scale)-len(digits)+1) + digits
+	}
+	point := len(digits) - int(d.scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+/*
+	The decimal rounded to exactly places digits after the decimal point, e.g. "0.10"
+*/
+func (d Decimal) StringFixed(places int32) string {
+	rounded := d.Round(places)
+	if rounded.scale < places {
+		rounded = newDecimal(rounded.rescale(places), places)
+	}
+	return rounded.String()
+}
+
+/*
+	Encoded as a JSON string, the way GDAX sends and expects amounts
+*/
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+/*
+	Decoded from a JSON string or number, an empty string or null is 0
+*/
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*d = Decimal{}
+		return nil
+	}
+	text := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		unquoted, err := strconv.Unquote(text)
+		if err != nil {
+			return fmt.Errorf("clients: invalid decimal %s", text)
+		}
+		text = unquoted
+	}
+	if text == "" {
+		*d = Decimal{}
+		return nil
+	}
+	parsed, err := NewDecimalFromString(text)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,175 @@
+package clients
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func Test_NewDecimalFromString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0.00000001", "0.00000001"},
+		{"10000.00", "10000.00"},
+		{"-123.450", "-123.450"},
+		{"+7", "7"},
+		{".5", "0.5"},
+		{"1e-8", "0.00000001"},
+		{"1.5E3", "1500"},
+		{"12345678901234567890.123456789", "12345678901234567890.123456789"},
+	}
+	for _, test := range tests {
+		actual, err := NewDecimalFromString(test.input)
+		if err != nil {
+			t.Fatalf("Error should be nil for %v, %v", test.input, err)
+		}
+		if actual.String() != test.expected {
+			t.Fatalf("Expected %v to parse as %v, actual = %v", test.input, test.expected, actual)
+		}
+	}
+	for _, input := range []string{"", "abc", "1.2.3", "--1", "1e", "0x10", "1e1001", "1e-1001", "1e2000000000", "1e-2147483648"} {
+		if _, err := NewDecimalFromString(input); err == nil {
+			t.Fatalf("Expected an error for %q", input)
+		}
+	}
+	// The exponent is bounded but the boundaries are valid
+	if d, err := NewDecimalFromString("1e-1000"); err != nil || d.Scale() != 1000 {
+		t.Fatalf("Expected 1e-1000 to parse with a scale of 1000, actual = %v %v", d.Scale(), err)
+	}
+	if _, err := NewDecimalFromString("1e1000"); err != nil {
+		t.Fatalf("Error should be nil for 1e1000, %v", err)
+	}
+}
+
+func Test_Decimal_arithmetic(t *testing.T) {
+	a := MustDecimal("0.1")
+	b := MustDecimal("0.2")
+	if sum := a.Add(b); sum.String() != "0.3" {
+		t.Fatalf("Expected 0.1 + 0.2 = 0.3, actual = %v", sum)
+	}
+	if difference := a.Sub(MustDecimal("0.00000001")); difference.String() != "0.09999999" {
+		t.Fatalf("Expected 0.09999999, actual = %v", difference)
+	}
+	if product := MustDecimal("333.99").Mul(MustDecimal("0.193")); product.String() != "64.46007" {
+		t.Fatalf("Expected 64.46007, actual = %v", product)
+	}
+	if quotient := NewDecimalFromInt(1).Div(NewDecimalFromInt(3)); quotient.String() != "0.3333333333333333" {
+		t.Fatalf("Expected 0.3333333333333333, actual = %v", quotient)
+	}
+	if quotient := NewDecimalFromInt(2).DivRound(NewDecimalFromInt(3), 2); quotient.String() != "0.67" {
+		t.Fatalf("Expected 0.67, actual = %v", quotient)
+	}
+	if quotient := NewDecimalFromInt(-2).DivRound(NewDecimalFromInt(3), 2); quotient.String() != "-0.67" {
+		t.Fatalf("Expected -0.67, actual = %v", quotient)
+	}
+	if negated := a.Neg(); negated.String() != "-0.1" || negated.Abs().String() != "0.1" {
+		t.Fatalf("Expected -0.1 and 0.1, actual = %v %v", negated, negated.Abs())
+	}
+	var zero Decimal
+	if !zero.IsZero() || zero.String() != "0" || zero.Add(a).String() != "0.1" {
+		t.Fatalf("Expected the zero value to be usable as 0")
+	}
+}
+
+func Test_Decimal_comparison(t *testing.T) {
+	if !MustDecimal("1.0").Equal(MustDecimal("1.00")) {
+		t.Fatalf("Expected 1.0 to equal 1.00")
+	}
+	if !MustDecimal("0.01").GreaterThan(MustDecimal("0.009")) {
+		t.Fatalf("Expected 0.01 > 0.009")
+	}
+	if !MustDecimal("-5").LessThan(Decimal{}) || MustDecimal("-5").Sign() != -1 {
+		t.Fatalf("Expected -5 < 0")
+	}
+}
+
+func Test_Decimal_rounding(t *testing.T) {
+	tests := []struct {
+		value    string
+		places   int32
+		round    string
+		truncate string
+	}{
+		{"1.005", 2, "1.01", "1.00"},
+		{"-1.005", 2, "-1.01", "-1.00"},
+		{"1.004", 2, "1.00", "1.00"},
+		{"2.5", 0, "3", "2"},
+		{"0.12", 4, "0.12", "0.12"},
+	}
+	for _, test := range tests {
+		d := MustDecimal(test.value)
+		if actual := d.Round(test.places).String(); actual != test.round {
+			t.Fatalf("Expected %v rounded to %d places = %v, actual = %v", test.value, test.places, test.round, actual)
+		}
+		if actual := d.Truncate(test.places).String(); actual != test.truncate {
+			t.Fatalf("Expected %v truncated to %d places = %v, actual = %v", test.value, test.places, test.truncate, actual)
+		}
+	}
+	if actual := MustDecimal("0.1").StringFixed(3); actual != "0.100" {
+		t.Fatalf("Expected 0.100, actual = %v", actual)
+	}
+	if actual := MustDecimal("333.987").FloorToIncrement(MustDecimal("0.01")); actual.String() != "333.98" {
+		t.Fatalf("Expected 333.98, actual = %v", actual)
+	}
+}
+
+func Test_Decimal_JSON(t *testing.T) {
+	type Row struct {
+		Price  Decimal `json:"price"`
+		Size   Decimal `json:"size"`
+		Fee    Decimal `json:"fee"`
+		Volume Decimal `json:"volume"`
+	}
+	row := Row{}
+	err := json.Unmarshal([]byte(`{ "price": "8500.12345678", "size": 0.01, "fee": "", "volume": null }`), &row)
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if row.Price.String() != "8500.12345678" || row.Size.String() != "0.01" || !row.Fee.IsZero() || !row.Volume.IsZero() {
+		t.Fatalf("Unexpected decoded row %+v", row)
+	}
+	data, err := json.Marshal(row)
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if string(data) != `{"price":"8500.12345678","size":"0.01","fee":"0","volume":"0"}` {
+		t.Fatalf("Unexpected encoded row %s", data)
+	}
+	if err := json.Unmarshal([]byte(`{ "price": "1.2.3" }`), &row); err == nil {
+		t.Fatalf("Expected an invalid decimal to be an error")
+	}
+}
+
+func Test_NewDecimal_negativeScale(t *testing.T) {
+	d := NewDecimal(5, -2)
+	if d.String() != "500" || d.Scale() != 0 {
+		t.Fatalf("Expected 500 with a scale of 0, actual = %v %v", d, d.Scale())
+	}
+	if d.Cmp(NewDecimalFromInt(500)) != 0 {
+		t.Fatalf("Expected %v to equal 500", d)
+	}
+	if actual := d.Add(MustDecimal("0.5")); actual.String() != "500.5" {
+		t.Fatalf("Expected 500.5, actual = %v", actual)
+	}
+}
+
+func Test_NewDecimalFromFloat(t *testing.T) {
+	if actual := NewDecimalFromFloat(0.1); actual.String() != "0.1" {
+		t.Fatalf("Expected 0.1, actual = %v", actual)
+	}
+	for _, value := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("Expected %v to panic", value)
+				}
+			}()
+			NewDecimalFromFloat(value)
+		}()
+	}
+	if actual := MustDecimal("0.25").Float64(); actual != 0.25 {
+		t.Fatalf("Expected 0.25, actual = %v", actual)
+	}
+}
@@ -0,0 +1,87 @@
+package clients
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+/*
+	Endpoints of a GDAX environment
+*/
+type Environment struct {
+	Name      string
+	Website   string
+	REST      string
+	Websocket string
+	FIX       string
+	// Prefix of the environment variables holding the API credentials, see EnvCredentialProvider
+	CredentialsPrefix string
+}
+
+const (
+	EnvironmentSandbox    = "sandbox"
+	EnvironmentProduction = "production"
+)
+
+var (
+	environments_mu sync.RWMutex
+	environments    = map[string]Environment{
+		EnvironmentSandbox: {
+			Name:              EnvironmentSandbox,
+			Website:           "https://public.sandbox.gdax.com",
+			REST:              "https://api-public.sandbox.gdax.com",
+			Websocket:         "wss://ws-feed-public.sandbox.gdax.com",
+			FIX:               "https://fix-public.sandbox.gdax.com",
+			CredentialsPrefix: "GDAX_SANDBOX",
+		},
+		EnvironmentProduction: {
+			Name:              EnvironmentProduction,
+			Website:           "https://gdax.com",
+			REST:              "https://api.gdax.com",
+			Websocket:         "wss://ws-feed.gdax.com",
+			FIX:               "tcp+ssl://fix.gdax.com:4198",
+			CredentialsPrefix: "GDAX_PRODUCTION",
+		},
+	}
+)
+
+/*
+	Add an environment to the registry, or replace the one with the same name
+*/
+func RegisterEnvironment(environment Environment) error {
+	if environment.Name == "" {
+		return fmt.Errorf("clients: environment name is required")
+	}
+	if environment.REST == "" {
+		return fmt.Errorf("clients: environment %s has no REST endpoint", environment.Name)
+	}
+	environments_mu.Lock()
+	defer environments_mu.Unlock()
+	environments[environment.Name] = environment
+	return nil
+}
+
+/*
+	Find an environment by name
+*/
+func LookupEnvironment(name string) (Environment, bool) {
+	environments_mu.RLock()
+	defer environments_mu.RUnlock()
+	environment, ok := environments[name]
+	return environment, ok
+}
+
+/*
+	Names of the registered environments, sorted
+*/
+func EnvironmentNames() []string {
+	environments_mu.RLock()
+	defer environments_mu.RUnlock()
+	names := make([]string, 0, len(environments))
+	for name := range environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
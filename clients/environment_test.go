@@ -0,0 +1,51 @@
+package clients
+
+import (
+	"testing"
+)
+
+func Test_LookupEnvironment(t *testing.T) {
+	sandbox, ok := LookupEnvironment(EnvironmentSandbox)
+	if !ok {
+		t.Fatalf("Expected the sandbox environment to be registered")
+	}
+	if sandbox.REST != "https://api-public.sandbox.gdax.com" {
+		t.Fatalf("Expected REST = https://api-public.sandbox.gdax.com, actual = %v", sandbox.REST)
+	}
+	if sandbox.Websocket != "wss://ws-feed-public.sandbox.gdax.com" {
+		t.Fatalf("Expected Websocket = wss://ws-feed-public.sandbox.gdax.com, actual = %v", sandbox.Websocket)
+	}
+	production, ok := LookupEnvironment(EnvironmentProduction)
+	if !ok {
+		t.Fatalf("Expected the production environment to be registered")
+	}
+	if production.FIX != "tcp+ssl://fix.gdax.com:4198" {
+		t.Fatalf("Expected FIX = tcp+ssl://fix.gdax.com:4198, actual = %v", production.FIX)
+	}
+	if _, ok := LookupEnvironment("staging"); ok {
+		t.Fatalf("Expected the staging environment to be unknown")
+	}
+}
+
+func Test_RegisterEnvironment(t *testing.T) {
+	err := RegisterEnvironment(Environment{Name: "local", REST: "http://localhost:8080"})
+	if nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	defer func() {
+		environments_mu.Lock()
+		delete(environments, "local")
+		environments_mu.Unlock()
+	}()
+	local, ok := LookupEnvironment("local")
+	if !ok || local.REST != "http://localhost:8080" {
+		t.Fatalf("Expected the local environment to be registered, actual = %v", local)
+	}
+	names := EnvironmentNames()
+	if len(names) != 3 || names[0] != "local" || names[1] != "production" || names[2] != "sandbox" {
+		t.Fatalf("Expected names = [local production sandbox], actual = %v", names)
+	}
+	if err := RegisterEnvironment(Environment{Name: "broken"}); nil == err {
+		t.Fatalf("Expected an error for an environment without a REST endpoint")
+	}
+}
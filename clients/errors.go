@@ -0,0 +1,164 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+/*
+	Error returned for every non-200 API response.
+
+	Message holds the "message" field of the JSON error body, or the trimmed raw body when
+	the server did not answer with JSON (e.g. an HTML page from a proxy or load balancer).
+*/
+type ClientError struct {
+	Message string `json:"message"`
+	// HTTP method of the failed request
+	Method string `json:"-"`
+	// Request path, without the query string
+	Path string `json:"-"`
+	// HTTP status code of the response
+	StatusCode int `json:"-"`
+	// Raw response body
+	Body []byte `json:"-"`
+	// Response headers, e.g. Retry-After or the CB-* headers
+	Header http.Header `json:"-"`
+	// Number of attempts made before giving up, see RetryPolicy
+	Attempts int `json:"-"`
+}
+
+func (e ClientError) Error() string {
+	if e.StatusCode == 0 {
+		return e.Message
+	}
+	status := fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message == "" {
+		return fmt.Sprintf("%s %s: %s", e.Method, e.Path, status)
+	}
+	return fmt.Sprintf("%s %s: %s: %s", e.Method, e.Path, status, e.Message)
+}
+
+/*
+	Build the error for a failed request from its response
+*/
+func newClientError(method, pathname string, res *http.Response, body_data []byte) ClientError {
+	client_error := parseClientError(body_data)
+	client_error.Method = method
+	client_error.Path = pathname
+	client_error.StatusCode = res.StatusCode
+	client_error.Header = res.Header
+	return client_error
+}
+
+/*
+	Decode the error response
+*/
+func (c *Client) decodeError(body_data []byte) error {
+	return error(parseClientError(body_data))
+}
+
+func parseClientError(body_data []byte) ClientError {
+	client_error := ClientError{}
+	reader := bytes.NewReader(body_data)
+	err := json.NewDecoder(reader).Decode(&client_error)
+	if err != nil {
+		client_error.Message = strings.TrimSpace(string(body_data))
+	}
+	client_error.Body = body_data
+	return client_error
+}
+
+/*
+	Extract the ClientError from err, if there is one
+*/
+func AsClientError(err error) (ClientError, bool) {
+	switch e := err.(type) {
+	case ClientError:
+		return e, true
+	case *ClientError:
+		if e != nil {
+			return *e, true
+		}
+	}
+	return ClientError{}, false
+}
+
+func hasStatusCode(err error, status_code int) bool {
+	client_error, ok := AsClientError(err)
+	return ok && client_error.StatusCode == status_code
+}
+
+func hasMessage(err error, message string) bool {
+	client_error, ok := AsClientError(err)
+	return ok && strings.EqualFold(strings.TrimSpace(client_error.Message), message)
+}
+
+/*
+	400 Bad Request – Invalid request format
+*/
+func IsBadRequest(err error) bool {
+	return hasStatusCode(err, http.StatusBadRequest)
+}
+
+/*
+	401 Unauthorized – Invalid API Key
+*/
+func IsUnauthorized(err error) bool {
+	return hasStatusCode(err, http.StatusUnauthorized)
+}
+
+/*
+	403 Forbidden – You do not have access to the requested resource
+*/
+func IsForbidden(err error) bool {
+	return hasStatusCode(err, http.StatusForbidden)
+}
+
+/*
+	404 Not Found
+*/
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+/*
+	429 Too Many Requests – the public or private rate limit was exceeded
+*/
+func IsRateLimited(err error) bool {
+	return hasStatusCode(err, http.StatusTooManyRequests)
+}
+
+/*
+	5xx – We had a problem with our server
+*/
+func IsServerError(err error) bool {
+	client_error, ok := AsClientError(err)
+	return ok && client_error.StatusCode >= 500 && client_error.StatusCode <= 599
+}
+
+/*
+	400 Bad Request with the message "Insufficient funds", returned when placing an order
+	or a withdrawal larger than the available balance
+*/
+func IsInsufficientFunds(err error) bool {
+	return hasStatusCode(err, http.StatusBadRequest) && hasMessage(err, "Insufficient funds")
+}
+
+/*
+	400 Bad Request with the message "Order already done", returned when canceling an order
+	that was filled or canceled in the meantime
+*/
+func IsOrderAlreadyDone(err error) bool {
+	return hasStatusCode(err, http.StatusBadRequest) && hasMessage(err, "Order already done")
+}
+
+/*
+	404 Not Found, or 400 Bad Request with the message "order not found", returned for an
+	unknown order id, or for a canceled order that never matched and was purged
+*/
+func IsOrderNotFound(err error) bool {
+	return IsNotFound(err) || (hasStatusCode(err, http.StatusBadRequest) && hasMessage(err, "order not found"))
+}
@@ -0,0 +1,109 @@
+package clients
+
+import (
+	"gopkg.in/jarcoal/httpmock.v1"
+	"net/http"
+	"testing"
+)
+
+func Test_ClientError_fromResponse(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/products/XXX-USD/ticker",
+		func(req *http.Request) (*http.Response, error) {
+			res := httpmock.NewStringResponse(404, `{ "message": "NotFound" }`)
+			res.Header.Set("CB-AFTER", "123")
+			return res, nil
+		},
+	)
+	client := NewMockClient()
+	_, err := GetProductTicker(client, "XXX-USD")
+	if nil == err {
+		t.Fatalf("Expected to return error, actual = %v", err)
+	}
+	client_error, ok := AsClientError(err)
+	if !ok {
+		t.Fatalf("Expected a ClientError, actual = %T", err)
+	}
+	if client_error.Method != "GET" {
+		t.Fatalf("Expected Method = GET, actual = %v", client_error.Method)
+	}
+	if client_error.Path != "/products/XXX-USD/ticker" {
+		t.Fatalf("Expected Path = /products/XXX-USD/ticker, actual = %v", client_error.Path)
+	}
+	if client_error.StatusCode != 404 {
+		t.Fatalf("Expected StatusCode = 404, actual = %v", client_error.StatusCode)
+	}
+	if client_error.Message != "NotFound" {
+		t.Fatalf("Expected Message = NotFound, actual = %v", client_error.Message)
+	}
+	if client_error.Header.Get("CB-AFTER") != "123" {
+		t.Fatalf("Expected the CB-AFTER header to be kept, actual = %v", client_error.Header)
+	}
+	if err.Error() != "GET /products/XXX-USD/ticker: 404 Not Found: NotFound" {
+		t.Fatalf("Unexpected error string, actual = %v", err.Error())
+	}
+	if !IsNotFound(err) {
+		t.Fatalf("Expected IsNotFound to be true")
+	}
+	if IsUnauthorized(err) || IsRateLimited(err) || IsServerError(err) {
+		t.Fatalf("Expected the other classifications to be false")
+	}
+}
+
+func Test_ClientError_nonJSONBody(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/time",
+		httpmock.NewStringResponder(502, "<html>Bad Gateway</html>\n"),
+	)
+	client := NewMockClient()
+	_, err := GetTime(client)
+	client_error, ok := AsClientError(err)
+	if !ok {
+		t.Fatalf("Expected a ClientError, actual = %v", err)
+	}
+	if client_error.Message != "<html>Bad Gateway</html>" {
+		t.Fatalf("Expected the raw body as message, actual = %v", client_error.Message)
+	}
+	if string(client_error.Body) != "<html>Bad Gateway</html>\n" {
+		t.Fatalf("Expected the raw body to be kept, actual = %v", string(client_error.Body))
+	}
+	if !IsServerError(err) {
+		t.Fatalf("Expected IsServerError to be true")
+	}
+}
+
+func Test_ClientError_classification(t *testing.T) {
+	tests := []struct {
+		err      error
+		check    func(error) bool
+		expected bool
+	}{
+		{ClientError{StatusCode: 401, Message: "Invalid API Key"}, IsUnauthorized, true},
+		{&ClientError{StatusCode: 429, Message: "Rate limit exceeded"}, IsRateLimited, true},
+		{ClientError{StatusCode: 400, Message: "Insufficient funds"}, IsInsufficientFunds, true},
+		{ClientError{StatusCode: 400, Message: "Invalid Price"}, IsInsufficientFunds, false},
+		{ClientError{StatusCode: 400, Message: "Invalid Price"}, IsBadRequest, true},
+		{ClientError{StatusCode: 403}, IsForbidden, true},
+		{ClientError{StatusCode: 500}, IsServerError, true},
+		{ClientError{StatusCode: 400, Message: "Order already done"}, IsOrderAlreadyDone, true},
+		{ClientError{StatusCode: 404, Message: "Order already done"}, IsOrderAlreadyDone, false},
+		{ClientError{StatusCode: 404, Message: "NotFound"}, IsOrderNotFound, true},
+		{ClientError{StatusCode: 400, Message: "order not found"}, IsOrderNotFound, true},
+		{ClientError{StatusCode: 400, Message: "Invalid order id"}, IsOrderNotFound, false},
+		{http.ErrHandlerTimeout, IsServerError, false},
+		{nil, IsNotFound, false},
+	}
+	for i, test := range tests {
+		if actual := test.check(test.err); actual != test.expected {
+			t.Fatalf("Test %d: expected %v, actual = %v for %v", i, test.expected, actual, test.err)
+		}
+	}
+}
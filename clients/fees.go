@@ -0,0 +1,199 @@
+package clients
+
+import (
+	"context"
+	"sort"
+)
+
+/*
+	Fees
+
+	Taker fees depend on the 30-day trailing volume of the user as a share of the 30-day volume
+	of the whole exchange, per product. Maker orders are free in every tier.
+
+	FEE SCHEDULE (BTC products)
+	| Volume share | Taker fee | Maker fee |
+	| 0% - 1%      | 0.25%     | 0%        |
+	| 1% - 2.5%    | 0.24%     | 0%        |
+	| 2.5% - 5%    | 0.22%     | 0%        |
+	| 5% - 10%     | 0.19%     | 0%        |
+	| 10% - 20%    | 0.15%     | 0%        |
+	| > 20%        | 0.10%     | 0%        |
+
+	Other products start at 0.30% and share the following tiers.
+*/
+
+/*
+	A tier of a fee schedule. Rates are fractions, e.g. 0.0025 for 0.25%.
+*/
+type FeeTier struct {
+	// Share of the exchange volume from which the tier applies, e.g. 0.01 for 1%
+	MinVolumeShare Decimal
+	MakerRate      Decimal
+	TakerRate      Decimal
+}
+
+/*
+	Fee tiers by product. Tiers must be sorted by increasing MinVolumeShare and the first one
+	should start at 0.
+*/
+type FeeSchedule struct {
+	// Tiers of the products without tiers of their own
+	Tiers []FeeTier
+	// Tiers of specific products, e.g. "BTC-USD"
+	ProductTiers map[string][]FeeTier
+}
+
+func newFeeTiers(base_taker_rate string) []FeeTier {
+	tiers := []FeeTier{}
+	for _, tier := range [][2]string{
+		{"0", base_taker_rate},
+		{"0.01", "0.0024"},
+		{"0.025", "0.0022"},
+		{"0.05", "0.0019"},
+		{"0.1", "0.0015"},
+		{"0.2", "0.0010"},
+	} {
+		tiers = append(tiers, FeeTier{
+			MinVolumeShare: MustDecimal(tier[0]),
+			MakerRate:      MustDecimal("0"),
+			TakerRate:      MustDecimal(tier[1]),
+		})
+	}
+	return tiers
+}
+
+/*
+	The published GDAX fee schedule, see above
+*/
+func DefaultFeeSchedule() *FeeSchedule {
+	btc_tiers := newFeeTiers("0.0025")
+	return &FeeSchedule{
+		Tiers: newFeeTiers("0.0030"),
+		ProductTiers: map[string][]FeeTier{
+			"BTC-USD": btc_tiers,
+			"BTC-EUR": btc_tiers,
+			"BTC-GBP": btc_tiers,
+		},
+	}
+}
+
+/*
+	Tiers applying to product_id
+*/
+func (s *FeeSchedule) TiersFor(product_id string) []FeeTier {
+	if tiers, ok := s.ProductTiers[product_id]; ok {
+		return tiers
+	}
+	return s.Tiers
+}
+
+/*
+	Current fee rates of a product, and what it takes to reach the next tier
+*/
+type FeeRates struct {
+	ProductID string
+	// Share of the exchange volume traded by the user
+	VolumeShare Decimal
+	// Index of the current tier in the schedule
+	Tier      int
+	MakerRate Decimal
+	TakerRate Decimal
+	// The next tier, nil in the last tier
+	NextTier *FeeTier
+	// Additional 30-day volume, in the base currency, needed to reach NextTier
+	VolumeToNextTier Decimal
+}
+
+/*
+	Fee rates for the trailing volume of a product
+*/
+func (s *FeeSchedule) Rates(volume AccountTrailingVolume) FeeRates {
+	tiers := s.TiersFor(volume.ProductID)
+	rates := FeeRates{ProductID: volume.ProductID}
+	if volume.ExchangeVolume.Sign() > 0 {
+		rates.VolumeShare = volume.Volume.Div(volume.ExchangeVolume)
+	}
+	// Index of the first tier starting above the share, the current tier is the previous one
+	next := sort.Search(len(tiers), func(i int) bool {
+		return tiers[i].MinVolumeShare.GreaterThan(rates.VolumeShare)
+	})
+	if next > 0 {
+		rates.Tier = next - 1
+		rates.MakerRate = tiers[next-1].MakerRate
+		rates.TakerRate = tiers[next-1].TakerRate
+	}
+	if next < len(tiers) {
+		rates.NextTier = &tiers[next]
+		rates.VolumeToNextTier = volumeToShare(volume, tiers[next].MinVolumeShare)
+	}
+	return rates
+}
+
+/*
+	Fee rates of every product of the trailing volume response
+*/
+func (s *FeeSchedule) RatesByProduct(volumes AccountTrailingVolumeResponse) map[string]FeeRates {
+	rates := map[string]FeeRates{}
+	for _, volume := range volumes {
+		rates[volume.ProductID] = s.Rates(volume)
+	}
+	return rates
+}
+
+/*
+	Additional volume x so that (volume + x) / (exchange_volume + x) reaches share, since the
+	volume traded by the user adds to the exchange volume as well: x = (share * E - V) / (1 - share).
+	The result is rounded up to 8 decimals.
+*/
+func volumeToShare(volume AccountTrailingVolume, share Decimal) Decimal {
+	one := NewDecimalFromInt(1)
+	if share.Cmp(one) >= 0 {
+		return Decimal{}
+	}
+	needed := share.Mul(volume.ExchangeVolume).Sub(volume.Volume)
+	if needed.Sign() <= 0 {
+		return Decimal{}
+	}
+	x := needed.Div(one.Sub(share))
+	if truncated := x.Truncate(8); !truncated.Equal(x) {
+		return truncated.Add(NewDecimal(1, 8))
+	}
+	return x.Truncate(8)
+}
+
+/*
+	Rate paid by an order providing (maker) or taking (taker) liquidity
+*/
+func (r FeeRates) Rate(liquidity Liquidity) Decimal {
+	if liquidity == LiquidityMaker {
+		return r.MakerRate
+	}
+	return r.TakerRate
+}
+
+/*
+	Projected fee, in the quote currency, of an order of size at price
+*/
+func (r FeeRates) Fee(liquidity Liquidity, price, size Decimal) Decimal {
+	return price.Mul(size).Mul(r.Rate(liquidity))
+}
+
+/*
+	Current fee rates of every product traded in the last 30 days according to schedule,
+	DefaultFeeSchedule when nil
+*/
+func GetFeeRates(client *Client, schedule *FeeSchedule) (map[string]FeeRates, error) {
+	return GetFeeRatesContext(context.Background(), client, schedule)
+}
+
+func GetFeeRatesContext(ctx context.Context, client *Client, schedule *FeeSchedule) (map[string]FeeRates, error) {
+	if schedule == nil {
+		schedule = DefaultFeeSchedule()
+	}
+	volumes, err := GetAccountTrailingVolumeContext(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return schedule.RatesByProduct(volumes), nil
+}
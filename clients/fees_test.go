@@ -0,0 +1,114 @@
+package clients
+
+import (
+	"gopkg.in/jarcoal/httpmock.v1"
+	"testing"
+)
+
+func Test_FeeSchedule_Rates(t *testing.T) {
+	schedule := DefaultFeeSchedule()
+
+	btc := schedule.Rates(AccountTrailingVolume{
+		ProductID:      "BTC-USD",
+		ExchangeVolume: MustDecimal("11800.00000000"),
+		Volume:         MustDecimal("100.00000000"),
+	})
+	if btc.Tier != 0 || !btc.TakerRate.Equal(MustDecimal("0.0025")) || !btc.MakerRate.IsZero() {
+		t.Fatalf("Expected the first tier at 0.25%%, actual = %+v", btc)
+	}
+	if btc.NextTier == nil || !btc.NextTier.TakerRate.Equal(MustDecimal("0.0024")) {
+		t.Fatalf("Expected the next tier at 0.24%%, actual = %+v", btc.NextTier)
+	}
+	if btc.VolumeToNextTier.String() != "18.18181819" {
+		t.Fatalf("Expected 18.18181819 more volume for the next tier, actual = %v", btc.VolumeToNextTier)
+	}
+	if fee := btc.Fee(LiquidityTaker, MustDecimal("10000.00"), MustDecimal("0.5")); !fee.Equal(MustDecimal("12.5")) {
+		t.Fatalf("Expected a 12.5 taker fee, actual = %v", fee)
+	}
+	if fee := btc.Fee(LiquidityMaker, MustDecimal("10000.00"), MustDecimal("0.5")); !fee.IsZero() {
+		t.Fatalf("Expected no maker fee, actual = %v", fee)
+	}
+
+	ltc := schedule.Rates(AccountTrailingVolume{
+		ProductID:      "LTC-USD",
+		ExchangeVolume: MustDecimal("51010.04100000"),
+		Volume:         MustDecimal("2010.04100000"),
+	})
+	if ltc.Tier != 2 || !ltc.TakerRate.Equal(MustDecimal("0.0022")) {
+		t.Fatalf("Expected the third tier at 0.22%%, actual = %+v", ltc)
+	}
+	if ltc.VolumeToNextTier.String() != "568.90636843" {
+		t.Fatalf("Expected 568.90636843 more volume for the next tier, actual = %v", ltc.VolumeToNextTier)
+	}
+
+	top := schedule.Rates(AccountTrailingVolume{
+		ProductID:      "ETH-USD",
+		ExchangeVolume: MustDecimal("10000"),
+		Volume:         MustDecimal("3000"),
+	})
+	if top.Tier != 5 || top.NextTier != nil || !top.VolumeToNextTier.IsZero() || !top.TakerRate.Equal(MustDecimal("0.001")) {
+		t.Fatalf("Expected the last tier at 0.10%%, actual = %+v", top)
+	}
+
+	idle := schedule.Rates(AccountTrailingVolume{ProductID: "ETH-USD"})
+	if idle.Tier != 0 || !idle.TakerRate.Equal(MustDecimal("0.003")) {
+		t.Fatalf("Expected the first tier at 0.30%% without volume, actual = %+v", idle)
+	}
+}
+
+func Test_FeeSchedule_Custom(t *testing.T) {
+	schedule := &FeeSchedule{
+		Tiers: []FeeTier{
+			{MinVolumeShare: MustDecimal("0"), MakerRate: MustDecimal("0.001"), TakerRate: MustDecimal("0.002")},
+			{MinVolumeShare: MustDecimal("0.5"), MakerRate: MustDecimal("0"), TakerRate: MustDecimal("0.001")},
+		},
+	}
+	rates := schedule.Rates(AccountTrailingVolume{
+		ProductID:      "BTC-USD",
+		ExchangeVolume: MustDecimal("100"),
+		Volume:         MustDecimal("50"),
+	})
+	if rates.Tier != 1 || !rates.MakerRate.IsZero() || rates.NextTier != nil {
+		t.Fatalf("Expected the last tier from a 50%% share, actual = %+v", rates)
+	}
+}
+
+func Test_GetFeeRates(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/users/self/trailing-volume",
+		httpmock.NewStringResponder(
+			200,
+			`
+				[
+					{
+						"product_id": "BTC-USD",
+						"exchange_volume": "11800.00000000",
+						"volume": "100.00000000",
+						"recorded_at": "1973-11-29T00:05:01.123456Z"
+					},
+					{
+						"product_id": "LTC-USD",
+						"exchange_volume": "51010.04100000",
+						"volume": "2010.04100000",
+						"recorded_at": "1973-11-29T00:05:02.123456Z"
+					}
+				]
+			`,
+		),
+	)
+	client := NewMockClient()
+	rates, err := GetFeeRates(client, nil)
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if len(rates) != 2 {
+		t.Fatalf("Expected rates for 2 products, actual = %v", rates)
+	}
+	if !rates["BTC-USD"].TakerRate.Equal(MustDecimal("0.0025")) || !rates["LTC-USD"].TakerRate.Equal(MustDecimal("0.0022")) {
+		t.Fatalf("Expected 0.25%% for BTC-USD and 0.22%% for LTC-USD, actual = %+v", rates)
+	}
+}
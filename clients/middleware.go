@@ -0,0 +1,154 @@
+package clients
+
+import (
+	"net/http"
+	"time"
+)
+
+/*
+	A single attempt of an API call as seen by the middleware.
+
+	Request is already signed when the middleware chain runs, so middleware may add headers
+	but must not change the method, URL or body. Once the chain returns, either Response and
+	Body are set, or Err is, or both for a non-200 response, in which case Err is a ClientError.
+*/
+type Call struct {
+	// HTTP method of the request
+	Method string
+	// Request path, without the query string
+	Path string
+	// Attempt number, starting at 1, see RetryPolicy
+	Attempt int
+	// The signed HTTP request
+	Request *http.Request
+	// The HTTP response, nil when the request failed before a response was received
+	Response *http.Response
+	// The raw response body
+	Body []byte
+	// Transport error, or the decoded ClientError for a non-200 response
+	Err error
+	// When the HTTP request was sent
+	Start time.Time
+	// How long the HTTP exchange took, including reading the response body
+	Duration time.Duration
+}
+
+/*
+	Executes a call, filling in its response, body and error
+*/
+type Handler func(call *Call)
+
+/*
+	Wraps a Handler to act before and after the next one.
+	A middleware may also answer the call itself without calling next, e.g. to inject faults.
+*/
+type Middleware func(next Handler) Handler
+
+/*
+	Append middleware to the chain, after (i.e. inside) the ones already registered
+*/
+func (c *Client) Use(middleware ...Middleware) {
+	c.Middleware = append(c.Middleware, middleware...)
+}
+
+/*
+	The send handler wrapped by the middleware chain
+*/
+func (c *Client) handler() Handler {
+	handler := Handler(c.send)
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		handler = c.Middleware[i](handler)
+	}
+	return handler
+}
+
+/*
+	Headers carrying credentials, they are never logged
+*/
+var secretHeaders = []string{
+	"CB-ACCESS-KEY",
+	"CB-ACCESS-SIGN",
+	"CB-ACCESS-PASSPHRASE",
+}
+
+/*
+	Copy header with the credential headers replaced by a placeholder
+*/
+func RedactHeader(header http.Header) http.Header {
+	redacted := http.Header{}
+	for key, values := range header {
+		redacted[key] = values
+	}
+	for _, key := range secretHeaders {
+		if redacted.Get(key) != "" {
+			redacted.Set(key, "[REDACTED]")
+		}
+	}
+	return redacted
+}
+
+/*
+	Anything that can print log lines, e.g. a *log.Logger
+*/
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+/*
+	Log every attempt with its status and duration. The request headers are logged as well,
+	with the API key, passphrase and signature redacted.
+*/
+func LoggingMiddleware(logger Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(call *Call) {
+			next(call)
+			url := ""
+			header := http.Header{}
+			if call.Request != nil {
+				url = call.Request.URL.String()
+				header = RedactHeader(call.Request.Header)
+			}
+			status := 0
+			if call.Response != nil {
+				status = call.Response.StatusCode
+			}
+			if call.Err != nil {
+				logger.Printf("gdax: %s %s attempt=%d status=%d duration=%v headers=%v error=%v", call.Method, url, call.Attempt, status, call.Duration, header, call.Err)
+				return
+			}
+			logger.Printf("gdax: %s %s attempt=%d status=%d duration=%v headers=%v", call.Method, url, call.Attempt, status, call.Duration, header)
+		}
+	}
+}
+
+/*
+	Report the duration of every attempt to observe, e.g. to feed a latency histogram.
+	The status code is 0 when no response was received.
+*/
+func TimingMiddleware(observe func(method, pathname string, status_code int, duration time.Duration, err error)) Middleware {
+	return func(next Handler) Handler {
+		return func(call *Call) {
+			start := time.Now()
+			next(call)
+			status := 0
+			if call.Response != nil {
+				status = call.Response.StatusCode
+			}
+			observe(call.Method, call.Path, status, time.Since(start), call.Err)
+		}
+	}
+}
+
+/*
+	Add header to every request, existing values are replaced
+*/
+func HeaderMiddleware(header http.Header) Middleware {
+	return func(next Handler) Handler {
+		return func(call *Call) {
+			for key, values := range header {
+				call.Request.Header[http.CanonicalHeaderKey(key)] = values
+			}
+			next(call)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package clients
+
+import (
+	"bytes"
+	"errors"
+	"gopkg.in/jarcoal/httpmock.v1"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Client_Middleware_order(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	var tenant string
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/time",
+		func(req *http.Request) (*http.Response, error) {
+			tenant = req.Header.Get("X-Tenant")
+			return httpmock.NewStringResponse(200, `{ "iso": "2015-01-07T23:47:25.201Z", "epoch": 1420674445.201 }`), nil
+		},
+	)
+	trace := []string{}
+	tracing := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(call *Call) {
+				trace = append(trace, "before "+name)
+				next(call)
+				trace = append(trace, "after "+name)
+			}
+		}
+	}
+	client := NewMockClient()
+	client.Use(tracing("outer"), tracing("inner"))
+	client.Use(HeaderMiddleware(http.Header{"X-Tenant": []string{"desk-1"}}))
+	if _, err := GetTime(client); nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	expected := []string{"before outer", "before inner", "after inner", "after outer"}
+	if strings.Join(trace, ",") != strings.Join(expected, ",") {
+		t.Fatalf("Expected trace %v, actual = %v", expected, trace)
+	}
+	if tenant != "desk-1" {
+		t.Fatalf("Expected the X-Tenant header to be injected, actual = %v", tenant)
+	}
+}
+
+func Test_Client_Middleware_faultInjection(t *testing.T) {
+	injected := errors.New("connection reset by peer")
+	client := NewMockClient()
+	client.Use(func(next Handler) Handler {
+		return func(call *Call) {
+			if call.Request.Header.Get("CB-ACCESS-SIGN") == "" {
+				t.Fatalf("Expected the request to be signed before the middleware runs")
+			}
+			call.Err = injected
+		}
+	})
+	_, err := GetTime(client)
+	if err != injected {
+		t.Fatalf("Expected the injected error, actual = %v", err)
+	}
+}
+
+func Test_LoggingMiddleware(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/users/self/trailing-volume",
+		httpmock.NewStringResponder(401, `{ "message": "Invalid API Key" }`),
+	)
+	output := &bytes.Buffer{}
+	client := NewMockClient()
+	client.Use(LoggingMiddleware(log.New(output, "", 0)))
+	_, err := GetAccountTrailingVolume(client)
+	if !IsUnauthorized(err) {
+		t.Fatalf("Expected an unauthorized error, actual = %v", err)
+	}
+	line := output.String()
+	if !strings.Contains(line, "GET https://mock-api.gdax.com/users/self/trailing-volume") || !strings.Contains(line, "status=401") {
+		t.Fatalf("Expected the request to be logged, actual = %v", line)
+	}
+	if strings.Contains(line, client.Key) || strings.Contains(line, client.Passphrase) {
+		t.Fatalf("Expected the credentials to be redacted, actual = %v", line)
+	}
+	if !strings.Contains(line, "[REDACTED]") {
+		t.Fatalf("Expected the redaction placeholder, actual = %v", line)
+	}
+}
+
+func Test_TimingMiddleware(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/time",
+		httpmock.NewStringResponder(200, `{ "iso": "2015-01-07T23:47:25.201Z", "epoch": 1420674445.201 }`),
+	)
+	observed := []string{}
+	client := NewMockClient()
+	client.Use(TimingMiddleware(func(method, pathname string, status_code int, duration time.Duration, err error) {
+		if duration < 0 {
+			t.Fatalf("Expected a positive duration, actual = %v", duration)
+		}
+		observed = append(observed, method+" "+pathname+" "+http.StatusText(status_code))
+	}))
+	if _, err := GetTime(client); nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if len(observed) != 1 || observed[0] != "GET /time OK" {
+		t.Fatalf("Expected one observation of GET /time, actual = %v", observed)
+	}
+}
+
+func Test_RedactHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set("CB-ACCESS-KEY", "key")
+	header.Set("CB-ACCESS-SIGN", "signature")
+	header.Set("CB-ACCESS-TIMESTAMP", "1420674445")
+	redacted := RedactHeader(header)
+	if redacted.Get("CB-ACCESS-KEY") != "[REDACTED]" || redacted.Get("CB-ACCESS-SIGN") != "[REDACTED]" {
+		t.Fatalf("Expected the credentials to be redacted, actual = %v", redacted)
+	}
+	if redacted.Get("CB-ACCESS-TIMESTAMP") != "1420674445" {
+		t.Fatalf("Expected the timestamp to be kept, actual = %v", redacted)
+	}
+	if header.Get("CB-ACCESS-KEY") != "key" {
+		t.Fatalf("Expected the original header to be left untouched, actual = %v", header)
+	}
+}
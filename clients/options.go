@@ -0,0 +1,146 @@
+package clients
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+	Configures a Client built by NewClient
+*/
+type ClientOption func(c *Client) error
+
+/*
+	Create a client configured by opts, which are applied in order.
+
+	Without WithEnvironment or WithBaseURL the client talks to the sandbox. The environment
+	also provides the credentials, read from its environment variables, unless other
+	credentials were given with WithCredentials or WithStaticCredentials.
+
+		client, err := NewClient(
+			WithEnvironment(EnvironmentProduction),
+			WithRateLimiter(NewRateLimiter()),
+			WithLogger(log.New(os.Stderr, "", log.LstdFlags)),
+		)
+*/
+func NewClient(opts ...ClientOption) (*Client, error) {
+	c := &Client{}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	if c.URL == "" {
+		if err := WithEnvironment(EnvironmentSandbox)(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+/*
+	Client for a registered environment, e.g. "sandbox" or "production"
+*/
+func WithEnvironment(name string) ClientOption {
+	return func(c *Client) error {
+		environment, ok := LookupEnvironment(name)
+		if !ok {
+			return fmt.Errorf("clients: unknown environment %q, expected one of %s", name, strings.Join(EnvironmentNames(), ", "))
+		}
+		c.URL = environment.REST
+		if c.Credentials == nil && c.Key == "" && environment.CredentialsPrefix != "" {
+			c.Credentials = EnvCredentialProvider{Prefix: environment.CredentialsPrefix}
+		}
+		return nil
+	}
+}
+
+/*
+	Client for the REST API at base_url, e.g. a proxy or a local test server
+*/
+func WithBaseURL(base_url string) ClientOption {
+	return func(c *Client) error {
+		if base_url == "" {
+			return fmt.Errorf("clients: base URL is required")
+		}
+		c.URL = strings.TrimRight(base_url, "/")
+		return nil
+	}
+}
+
+func WithCredentials(provider CredentialProvider) ClientOption {
+	return func(c *Client) error {
+		c.Credentials = provider
+		return nil
+	}
+}
+
+func WithStaticCredentials(key, secret, passphrase string) ClientOption {
+	return WithCredentials(StaticCredentialProvider{Key: key, Secret: secret, Passphrase: passphrase})
+}
+
+func WithHTTPClient(doer Doer) ClientOption {
+	return func(c *Client) error {
+		c.HTTPClient = doer
+		return nil
+	}
+}
+
+func WithUserAgent(user_agent string) ClientOption {
+	return func(c *Client) error {
+		c.UserAgent = user_agent
+		return nil
+	}
+}
+
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *Client) error {
+		c.RateLimiter = limiter
+		return nil
+	}
+}
+
+func WithRetryPolicy(policy *RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		c.RetryPolicy = policy
+		return nil
+	}
+}
+
+func WithServerClock(clock *ServerClock) ClientOption {
+	return func(c *Client) error {
+		c.Clock = clock
+		return nil
+	}
+}
+
+func WithCache(cache *Cache) ClientOption {
+	return func(c *Client) error {
+		c.Cache = cache
+		return nil
+	}
+}
+
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *Client) error {
+		c.Use(middleware...)
+		return nil
+	}
+}
+
+/*
+	Log every request with LoggingMiddleware
+*/
+func WithLogger(logger Logger) ClientOption {
+	return WithMiddleware(LoggingMiddleware(logger))
+}
+
+/*
+	Scope the private requests of the client to a profile, see Client.ForProfile
+*/
+func WithProfile(profile_id string) ClientOption {
+	return func(c *Client) error {
+		c.ProfileID = profile_id
+		return nil
+	}
+}
@@ -0,0 +1,123 @@
+package clients
+
+import (
+	"gopkg.in/jarcoal/httpmock.v1"
+	"net/http"
+	"testing"
+)
+
+func Test_NewClient_Defaults(t *testing.T) {
+	client, err := NewClient()
+	if nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if client.URL != "https://api-public.sandbox.gdax.com" {
+		t.Fatalf("Expected URL = https://api-public.sandbox.gdax.com, actual = %v", client.URL)
+	}
+	provider, ok := client.Credentials.(EnvCredentialProvider)
+	if !ok || provider.Prefix != "GDAX_SANDBOX" {
+		t.Fatalf("Expected credentials from GDAX_SANDBOX_*, actual = %#v", client.Credentials)
+	}
+}
+
+func Test_NewClient_Environment(t *testing.T) {
+	client, err := NewClient(
+		WithStaticCredentials("key", "c2VjcmV0", "passphrase"),
+		WithEnvironment(EnvironmentProduction),
+	)
+	if nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if client.URL != "https://api.gdax.com" {
+		t.Fatalf("Expected URL = https://api.gdax.com, actual = %v", client.URL)
+	}
+	if _, ok := client.Credentials.(StaticCredentialProvider); !ok {
+		t.Fatalf("Expected the static credentials to be kept, actual = %#v", client.Credentials)
+	}
+
+	_, err = NewClient(WithEnvironment("staging"))
+	if nil == err {
+		t.Fatalf("Expected an error for an unknown environment")
+	}
+}
+
+func Test_NewClient_Options(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	var user_agent, key string
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/time",
+		func(req *http.Request) (*http.Response, error) {
+			user_agent = req.Header.Get("User-Agent")
+			key = req.Header.Get("CB-ACCESS-KEY")
+			return httpmock.NewStringResponse(200, `{ "iso": "2015-01-07T23:47:25.201Z", "epoch": 1420674445.201 }`), nil
+		},
+	)
+	doer := &recordingDoer{doer: DefaultHTTPClient}
+	limiter := NewRateLimiter()
+	client, err := NewClient(
+		WithBaseURL("https://mock-api.gdax.com/"),
+		WithStaticCredentials("key", "c2VjcmV0", "passphrase"),
+		WithHTTPClient(doer),
+		WithUserAgent("gdax-api-test/1.0"),
+		WithRateLimiter(limiter),
+	)
+	if nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if client.URL != "https://mock-api.gdax.com" {
+		t.Fatalf("Expected URL = https://mock-api.gdax.com, actual = %v", client.URL)
+	}
+	if client.RateLimiter != limiter {
+		t.Fatalf("Expected the rate limiter to be set")
+	}
+	_, err = GetTime(client)
+	if nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if len(doer.requests) != 1 {
+		t.Fatalf("Expected 1 request to go through the custom doer, actual = %v", len(doer.requests))
+	}
+	if user_agent != "gdax-api-test/1.0" {
+		t.Fatalf("Expected User-Agent = gdax-api-test/1.0, actual = %v", user_agent)
+	}
+	if key != "key" {
+		t.Fatalf("Expected CB-ACCESS-KEY = key, actual = %v", key)
+	}
+}
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, format)
+}
+
+func Test_NewClient_WithLogger(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/time",
+		httpmock.NewStringResponder(
+			200,
+			`{ "iso": "2015-01-07T23:47:25.201Z", "epoch": 1420674445.201 }`,
+		),
+	)
+	logger := &recordingLogger{}
+	client, err := NewClient(WithBaseURL("https://mock-api.gdax.com"), WithLogger(logger))
+	if nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	_, err = GetTime(client)
+	if nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if len(logger.lines) == 0 {
+		t.Fatalf("Expected the request to be logged")
+	}
+}
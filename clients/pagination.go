@@ -0,0 +1,212 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+)
+
+/*
+	Pagination
+
+	GDAX uses cursor pagination for all REST requests which return arrays. Cursor pagination allows
+	for fetching results before and after the current page of results and is well suited for realtime
+	data. Endpoints like /trades, /fills, /orders, return the latest items by default. To retrieve
+	more results subsequent requests should specify which direction to paginate based on the data
+	previously returned.
+
+	before and after cursors are available via response headers CB-BEFORE and CB-AFTER. Your requests
+	should use these cursor values when making requests for pages after the initial request.
+
+	PARAMETERS
+	| Parameter | Default | Description                                                |
+	| before    |         | Request page before (newer) this pagination id.            |
+	| after     |         | Request page after (older) this pagination id.             |
+	| limit     | 100     | Number of results per request. Maximum 100. (default 100)  |
+
+	Example
+		GET /orders?before=2&limit=30
+*/
+type Pagination struct {
+	Before string
+	After  string
+	Limit  int
+}
+
+/*
+	Maximum number of results per page
+*/
+const MaxPaginationLimit = 100
+
+/*
+	Returned by a ForEach callback to stop the iteration early without an error
+*/
+var ErrStopIteration = errors.New("clients: stop iteration")
+
+/*
+	Add the pagination parameters to params
+*/
+func (p Pagination) apply(params url.Values) {
+	if p.Before != "" {
+		params.Set("before", p.Before)
+	}
+	if p.After != "" {
+		params.Set("after", p.After)
+	}
+	if p.Limit > 0 {
+		params.Set("limit", strconv.Itoa(p.Limit))
+	}
+}
+
+/*
+	Cursors returned with a page of results.
+	Before points to newer results and After to older results, either is empty when there are none.
+*/
+type Cursor struct {
+	Before string
+	After  string
+}
+
+func cursorFromResponse(res *http.Response) Cursor {
+	if res == nil {
+		return Cursor{}
+	}
+	return Cursor{
+		Before: res.Header.Get("CB-BEFORE"),
+		After:  res.Header.Get("CB-AFTER"),
+	}
+}
+
+/*
+	Walks the pages of a list endpoint.
+
+	By default it starts from the page selected by the initial Pagination and moves towards older
+	results using the CB-AFTER cursor. With Newer set it moves towards newer results using the
+	CB-BEFORE cursor instead, e.g. to poll for items created since a known id.
+*/
+type Paginator struct {
+	// Walk towards newer results instead of older results
+	Newer bool
+	// Cursors of the last page fetched
+	Cursor Cursor
+
+	client     *Client
+	pathname   string
+	params     url.Values
+	pagination Pagination
+	started    bool
+	done       bool
+}
+
+/*
+	Create a paginator for GET pathname with the given query parameters
+*/
+func NewPaginator(client *Client, pathname string, params url.Values, pagination Pagination) *Paginator {
+	copied := url.Values{}
+	for key, values := range params {
+		copied[key] = append([]string{}, values...)
+	}
+	return &Paginator{
+		client:     client,
+		pathname:   pathname,
+		params:     copied,
+		pagination: pagination,
+	}
+}
+
+/*
+	Whether there may be more pages to fetch
+*/
+func (p *Paginator) HasNext() bool {
+	return !p.done
+}
+
+/*
+	Fetch the next page into result, which must be a pointer to a slice.
+
+	Returns false without making a request once all pages have been fetched, and true otherwise,
+	even when the page turns out to be empty.
+*/
+func (p *Paginator) Next(ctx context.Context, result interface{}) (bool, error) {
+	if p.done {
+		return false, nil
+	}
+	if p.started {
+		if p.Newer {
+			p.pagination.Before = p.Cursor.Before
+			p.pagination.After = ""
+		} else {
+			p.pagination.Before = ""
+			p.pagination.After = p.Cursor.After
+		}
+	}
+	params := url.Values{}
+	for key, values := range p.params {
+		params[key] = values
+	}
+	p.pagination.apply(params)
+	// Start from an empty page, JSON decoding into the elements of the previous page would keep
+	// the fields the new items leave out
+	if value := reflect.ValueOf(result); value.Kind() == reflect.Ptr && !value.IsNil() {
+		value.Elem().Set(reflect.Zero(value.Elem().Type()))
+	}
+	res, err := p.client.GetContext(ctx, p.pathname, params, result)
+	if err != nil {
+		return false, err
+	}
+	p.started = true
+	p.Cursor = cursorFromResponse(res)
+	next := p.Cursor.After
+	if p.Newer {
+		next = p.Cursor.Before
+	}
+	if next == "" || sliceLen(result) == 0 {
+		p.done = true
+	}
+	return true, nil
+}
+
+/*
+	Call fn with every item of every remaining page.
+
+	page must be a pointer to a slice, it is reused for every page and fn receives its elements.
+	The iteration stops at the first error returned by fn; ErrStopIteration stops it without error.
+*/
+func (p *Paginator) ForEach(ctx context.Context, page interface{}, fn func(item interface{}) error) error {
+	for {
+		ok, err := p.Next(ctx, page)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		items := reflect.ValueOf(page).Elem()
+		for i := 0; i < items.Len(); i++ {
+			if err := fn(items.Index(i).Interface()); err != nil {
+				if err == ErrStopIteration {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+}
+
+func sliceLen(result interface{}) int {
+	value := reflect.ValueOf(result)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return 0
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		panic(fmt.Sprintf("clients: paginated result must be a pointer to a slice, got %T", result))
+	}
+	return value.Len()
+}
@@ -0,0 +1,181 @@
+package clients
+
+import (
+	"context"
+	"gopkg.in/jarcoal/httpmock.v1"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+/*
+	Serve the trades of BTC-USD in pages of two, newest first, using trade ids as cursors
+*/
+func registerPaginatedTrades(t *testing.T, queries *[]url.Values) {
+	pages := map[string]string{
+		"": `[
+			{ "time": "2014-11-07T22:19:28.578544Z", "trade_id": 5, "price": "10.00", "size": "0.01", "side": "buy" },
+			{ "time": "2014-11-07T22:19:27.578544Z", "trade_id": 4, "price": "11.00", "size": "0.02", "side": "sell" }
+		]`,
+		"4": `[
+			{ "time": "2014-11-07T22:19:26.578544Z", "trade_id": 3, "price": "12.00", "size": "0.03", "side": "buy" },
+			{ "time": "2014-11-07T22:19:25.578544Z", "trade_id": 2, "price": "13.00", "size": "0.04", "side": "sell" }
+		]`,
+		"2": `[
+			{ "time": "2014-11-07T22:19:24.578544Z", "trade_id": 1, "price": "14.00", "size": "0.05", "side": "buy" }
+		]`,
+	}
+	cursors := map[string][2]string{
+		"":  {"5", "4"},
+		"4": {"3", "2"},
+		"2": {"1", ""},
+	}
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/products/BTC-USD/trades",
+		func(req *http.Request) (*http.Response, error) {
+			query := req.URL.Query()
+			*queries = append(*queries, query)
+			after := query.Get("after")
+			body, ok := pages[after]
+			if !ok {
+				t.Fatalf("Unexpected after cursor %v", after)
+			}
+			res := httpmock.NewStringResponse(200, body)
+			res.Header.Set("CB-BEFORE", cursors[after][0])
+			if cursors[after][1] != "" {
+				res.Header.Set("CB-AFTER", cursors[after][1])
+			}
+			return res, nil
+		},
+	)
+}
+
+func Test_GetProductTradesPage(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	queries := []url.Values{}
+	registerPaginatedTrades(t, &queries)
+	client := NewMockClient()
+	output, cursor, err := GetProductTradesPage(client, "BTC-USD", Pagination{After: "4", Limit: 2})
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if len(output) != 2 || output[0].TradeID != 3 {
+		t.Fatalf("Expected the page after trade 4, actual = %v", output)
+	}
+	if cursor.Before != "3" || cursor.After != "2" {
+		t.Fatalf("Expected cursor {3 2}, actual = %v", cursor)
+	}
+	if queries[0].Get("after") != "4" || queries[0].Get("limit") != "2" || queries[0].Get("before") != "" {
+		t.Fatalf("Unexpected pagination parameters %v", queries[0])
+	}
+}
+
+func Test_Paginator_Next(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	queries := []url.Values{}
+	registerPaginatedTrades(t, &queries)
+	client := NewMockClient()
+	paginator := NewProductTradesPaginator(client, "BTC-USD", Pagination{Limit: 2})
+	trade_ids := []int{}
+	pages := 0
+	for paginator.HasNext() {
+		page := GdaxProductTradesResponse{}
+		ok, err := paginator.Next(context.Background(), &page)
+		if err != nil {
+			t.Fatalf("Error should be nil, %v", err)
+		}
+		if !ok {
+			break
+		}
+		pages += 1
+		for _, trade := range page {
+			trade_ids = append(trade_ids, trade.TradeID)
+		}
+	}
+	if pages != 3 {
+		t.Fatalf("Expected 3 pages, actual = %v", pages)
+	}
+	if len(trade_ids) != 5 || trade_ids[0] != 5 || trade_ids[4] != 1 {
+		t.Fatalf("Expected trades 5 to 1, actual = %v", trade_ids)
+	}
+	for _, query := range queries {
+		if query.Get("limit") != "2" {
+			t.Fatalf("Expected the limit to be sent with every page, actual = %v", query)
+		}
+	}
+	if ok, err := paginator.Next(context.Background(), &GdaxProductTradesResponse{}); ok || err != nil {
+		t.Fatalf("Expected the paginator to be exhausted, actual = %v %v", ok, err)
+	}
+}
+
+func Test_Paginator_ForEach(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	queries := []url.Values{}
+	registerPaginatedTrades(t, &queries)
+	client := NewMockClient()
+	paginator := NewProductTradesPaginator(client, "BTC-USD", Pagination{})
+	trade_ids := []int{}
+	err := paginator.ForEach(context.Background(), &GdaxProductTradesResponse{}, func(item interface{}) error {
+		trade := item.(GdaxProductTrade)
+		if trade.TradeID < 3 {
+			return ErrStopIteration
+		}
+		trade_ids = append(trade_ids, trade.TradeID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if len(trade_ids) != 3 {
+		t.Fatalf("Expected trades 5 to 3, actual = %v", trade_ids)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("Expected to stop after the second page, actual requests = %v", len(queries))
+	}
+}
+
+func Test_Paginator_ForEach_resetsPage(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/products/BTC-USD/trades",
+		func(req *http.Request) (*http.Response, error) {
+			if req.URL.Query().Get("after") == "" {
+				res := httpmock.NewStringResponse(200, `[
+					{ "time": "2014-11-07T22:19:28.578544Z", "trade_id": 2, "price": "10.00", "size": "0.01", "side": "buy" }
+				]`)
+				res.Header.Set("CB-AFTER", "2")
+				return res, nil
+			}
+			// The items of the second page leave out the side and the size
+			return httpmock.NewStringResponse(200, `[
+				{ "time": "2014-11-07T22:19:27.578544Z", "trade_id": 1, "price": "11.00" }
+			]`), nil
+		},
+	)
+	client := NewMockClient()
+	paginator := NewProductTradesPaginator(client, "BTC-USD", Pagination{})
+	trades := []GdaxProductTrade{}
+	err := paginator.ForEach(context.Background(), &GdaxProductTradesResponse{}, func(item interface{}) error {
+		trades = append(trades, item.(GdaxProductTrade))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if len(trades) != 2 {
+		t.Fatalf("Expected 2 trades, actual = %v", trades)
+	}
+	if trades[1].Side != "" || !trades[1].Size.IsZero() {
+		t.Fatalf("Expected the second trade to have no side and size, actual = %v", trades[1])
+	}
+}
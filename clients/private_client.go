@@ -1,11 +1,492 @@
 package clients
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"time"
 )
 
+type Account struct {
+	ID        string  `json:"id"`
+	Currency  string  `json:"currency"`
+	Balance   Decimal `json:"balance"`
+	Available Decimal `json:"available"`
+	Hold      Decimal `json:"hold"`
+	ProfileID string  `json:"profile_id"`
+}
+type AccountsResponse []Account
+
+/*
+	List Accounts
+	HTTP REQUEST
+		GET /accounts
+
+	HTTP RESPONSE
+	[
+		{
+			"id": "71452118-efc7-4cc4-8780-a5e22d4baa53",
+			"currency": "BTC",
+			"balance": "0.0000000000000000",
+			"available": "0.0000000000000000",
+			"hold": "0.0000000000000000",
+			"profile_id": "75da88c5-05bf-4f54-bc85-5c775bd68254"
+		},
+		{
+			"id": "e316cb9a-0808-4fd7-8914-97829c1925de",
+			"currency": "USD",
+			"balance": "80.2301373066930000",
+			"available": "79.2266348066930000",
+			"hold": "1.0035025000000000",
+			"profile_id": "75da88c5-05bf-4f54-bc85-5c775bd68254"
+		}
+	]
+
+	FIELDS
+	| Field | Description |
+	| id | Account ID |
+	| currency | The currency of the account |
+	| balance | Total funds in the account |
+	| available | Funds available to withdraw or trade |
+	| hold | Funds on hold (not available for use) |
+	| profile_id | The ID of the profile the account belongs to |
+
+	Funds on hold are reserved for open orders and pending withdrawals, so available is balance minus hold.
+*/
+func GetAccounts(client *Client) (AccountsResponse, error) {
+	return GetAccountsContext(context.Background(), client)
+}
+
+func GetAccountsContext(ctx context.Context, client *Client) (AccountsResponse, error) {
+	pathname := "/accounts"
+	params := url.Values{}
+	output := []Account{}
+	_, err := client.GetContext(ctx, pathname, params, &output)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+/*
+	Get an Account
+	HTTP REQUEST
+		GET /accounts/:account_id
+
+	HTTP RESPONSE
+	{
+		"id": "e316cb9a-0808-4fd7-8914-97829c1925de",
+		"currency": "USD",
+		"balance": "1.100",
+		"available": "1.00",
+		"hold": "0.100",
+		"profile_id": "75da88c5-05bf-4f54-bc85-5c775bd68254"
+	}
+*/
+func GetAccount(client *Client, account_id string) (*Account, error) {
+	return GetAccountContext(context.Background(), client, account_id)
+}
+
+func GetAccountContext(ctx context.Context, client *Client, account_id string) (*Account, error) {
+	pathname := fmt.Sprintf("/accounts/%s", account_id)
+	params := url.Values{}
+	output := &Account{}
+	_, err := client.GetContext(ctx, pathname, params, output)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+/*
+	The account holding currency, nil when there is none
+*/
+func (r AccountsResponse) Find(currency string) *Account {
+	for i := range r {
+		if r[i].Currency == currency {
+			return &r[i]
+		}
+	}
+	return nil
+}
+
+/*
+	Kind of a ledger entry
+*/
+type LedgerEntryType string
+
+const (
+	// Funds moved as the result of a trade
+	LedgerEntryMatch LedgerEntryType = "match"
+	// Fee as the result of a trade
+	LedgerEntryFee LedgerEntryType = "fee"
+	// Funds moved to or from Coinbase to GDAX
+	LedgerEntryTransfer LedgerEntryType = "transfer"
+	// Fee rebate as per the fee schedule
+	LedgerEntryRebate LedgerEntryType = "rebate"
+)
+
+type LedgerEntry struct {
+	ID        string             `json:"id"`
+	CreatedAt time.Time          `json:"created_at"`
+	Amount    Decimal            `json:"amount"`
+	Balance   Decimal            `json:"balance"`
+	Type      LedgerEntryType    `json:"type"`
+	Details   LedgerEntryDetails `json:"details"`
+}
+type AccountLedgerResponse []LedgerEntry
+
+/*
+	Origin of a ledger entry: the order, trade and product of a match, fee or rebate,
+	or the transfer of a transfer
+*/
+type LedgerEntryDetails struct {
+	OrderID      string
+	TradeID      string
+	ProductID    string
+	TransferID   string
+	TransferType string
+}
+
+/*
+	Decoded from the details object, where ids may be sent as strings or numbers
+*/
+func (d *LedgerEntryDetails) UnmarshalJSON(data []byte) error {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	// The details of another entry may be decoded into d, do not keep them
+	*d = LedgerEntryDetails{}
+	fields := map[string]*string{
+		"order_id":      &d.OrderID,
+		"trade_id":      &d.TradeID,
+		"product_id":    &d.ProductID,
+		"transfer_id":   &d.TransferID,
+		"transfer_type": &d.TransferType,
+	}
+	for name, field := range fields {
+		value, ok := raw[name]
+		if !ok {
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(value, &s); err == nil {
+			*field = s
+			continue
+		}
+		var n json.Number
+		if err := json.Unmarshal(value, &n); err != nil {
+			return fmt.Errorf("clients: invalid ledger entry %s %s", name, value)
+		}
+		*field = n.String()
+	}
+	return nil
+}
+
+/*
+	Get Account History
+	List account activity. Account activity either increases or decreases your account balance.
+	Items are paginated and sorted latest first.
+
+	HTTP REQUEST
+		GET /accounts/:account_id/ledger
+
+	HTTP RESPONSE
+	[
+		{
+			"id": "100",
+			"created_at": "2014-11-07T08:19:27.028459Z",
+			"amount": "0.001",
+			"balance": "239.669",
+			"type": "fee",
+			"details": {
+				"order_id": "d50ec984-77a8-460a-b958-66f114b0de9b",
+				"trade_id": "74",
+				"product_id": "BTC-USD"
+			}
+		}
+	]
+
+	ENTRY TYPES
+	| Type | Description |
+	| transfer | Funds moved to/from Coinbase to GDAX |
+	| match | Funds moved as a result of a trade |
+	| fee | Fee as a result of a trade |
+	| rebate | Fee rebate as per our fee schedule |
+
+	DETAILS
+	If an entry is the result of a trade (match, fee), the details field will contain additional
+	information about the trade.
+
+	PAGINATION
+	This request is paginated, the cursors of the returned page are read from the CB-BEFORE and CB-AFTER headers.
+*/
+func GetAccountLedger(client *Client, account_id string) (AccountLedgerResponse, error) {
+	output, _, err := GetAccountLedgerPageContext(context.Background(), client, account_id, Pagination{})
+	return output, err
+}
+
+func GetAccountLedgerPage(client *Client, account_id string, pagination Pagination) (AccountLedgerResponse, Cursor, error) {
+	return GetAccountLedgerPageContext(context.Background(), client, account_id, pagination)
+}
+
+func GetAccountLedgerPageContext(ctx context.Context, client *Client, account_id string, pagination Pagination) (AccountLedgerResponse, Cursor, error) {
+	params := url.Values{}
+	pagination.apply(params)
+	output := AccountLedgerResponse{}
+	res, err := client.GetContext(ctx, fmt.Sprintf("/accounts/%s/ledger", account_id), params, &output)
+	if err != nil {
+		return AccountLedgerResponse{}, Cursor{}, err
+	}
+	return output, cursorFromResponse(res), nil
+}
+
+/*
+	Walk the ledger of an account page by page, from the latest entry towards the oldest.
+	Pages are decoded into a *AccountLedgerResponse.
+*/
+func NewAccountLedgerPaginator(client *Client, account_id string, pagination Pagination) *Paginator {
+	return NewPaginator(client, fmt.Sprintf("/accounts/%s/ledger", account_id), url.Values{}, pagination)
+}
+
+/*
+	Iterates over the ledger entries of an account created in [Start, End), latest first.
+
+	The ledger cannot be filtered by time on the server, so the iterator walks the pages from the
+	latest entry, skips the entries created at or after End and stops at the first one created
+	before Start. A zero Start or End leaves that side of the range open.
+*/
+type AccountLedgerIterator struct {
+	Start time.Time
+	End   time.Time
+
+	paginator *Paginator
+	page      AccountLedgerResponse
+	index     int
+	done      bool
+}
+
+func NewAccountLedgerIterator(client *Client, account_id string, start, end time.Time) *AccountLedgerIterator {
+	return &AccountLedgerIterator{
+		Start:     start,
+		End:       end,
+		paginator: NewAccountLedgerPaginator(client, account_id, Pagination{Limit: MaxPaginationLimit}),
+	}
+}
+
+/*
+	The next entry in the range, false once the range has been walked
+*/
+func (it *AccountLedgerIterator) Next(ctx context.Context) (LedgerEntry, bool, error) {
+	for !it.done {
+		if it.index >= len(it.page) {
+			it.page = nil
+			it.index = 0
+			ok, err := it.paginator.Next(ctx, &it.page)
+			if err != nil {
+				return LedgerEntry{}, false, err
+			}
+			if !ok {
+				it.done = true
+			}
+			continue
+		}
+		entry := it.page[it.index]
+		it.index++
+		if !it.End.IsZero() && !entry.CreatedAt.Before(it.End) {
+			continue
+		}
+		if !it.Start.IsZero() && entry.CreatedAt.Before(it.Start) {
+			it.done = true
+			break
+		}
+		return entry, true, nil
+	}
+	return LedgerEntry{}, false, nil
+}
+
+/*
+	Call fn with every remaining entry in the range.
+	The iteration stops at the first error returned by fn; ErrStopIteration stops it without error.
+*/
+func (it *AccountLedgerIterator) ForEach(ctx context.Context, fn func(entry LedgerEntry) error) error {
+	for {
+		entry, ok, err := it.Next(ctx)
+		if err != nil || !ok {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			if err == ErrStopIteration {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+/*
+	Kind of a hold
+*/
+type HoldType string
+
+const (
+	// Funds reserved for an open order, Ref is the order id
+	HoldOrder HoldType = "order"
+	// Funds reserved for a pending withdrawal, Ref is the transfer id
+	HoldTransfer HoldType = "transfer"
+)
+
+type AccountHold struct {
+	ID        string    `json:"id"`
+	AccountID string    `json:"account_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Amount    Decimal   `json:"amount"`
+	Type      HoldType  `json:"type"`
+	Ref       string    `json:"ref"`
+}
+type AccountHoldsResponse []AccountHold
+
+/*
+	Id of the order holding the funds, empty for other holds
+*/
+func (h AccountHold) OrderID() string {
+	if h.Type == HoldOrder {
+		return h.Ref
+	}
+	return ""
+}
+
+/*
+	Id of the transfer holding the funds, empty for other holds
+*/
+func (h AccountHold) TransferID() string {
+	if h.Type == HoldTransfer {
+		return h.Ref
+	}
+	return ""
+}
+
+/*
+	Get Holds
+	Holds are placed on an account for any active orders or pending withdraw requests. As an order
+	is filled, the hold amount is updated. If an order is canceled, any remaining hold is removed.
+	For a withdraw, once it is completed, the hold is removed.
+
+	HTTP REQUEST
+		GET /accounts/:account_id/holds
+
+	HTTP RESPONSE
+	[
+		{
+			"id": "82dcd140-c3c7-4507-8de4-2c529cd1a28f",
+			"account_id": "e0b3f39a-183d-453e-b754-0c13e5bab0b3",
+			"created_at": "2014-11-06T10:34:47.123456Z",
+			"updated_at": "2014-11-06T10:40:47.123456Z",
+			"amount": "4.23",
+			"type": "order",
+			"ref": "0a205de4-dd35-4370-a285-fe8fc375a273"
+		}
+	]
+
+	TYPE
+	The type of the hold will indicate why the hold exists. The hold type is order for holds
+	related to open orders and transfer for holds related to a withdraw.
+
+	REF
+	The ref field contains the id of the order or transfer which created the hold.
+
+	PAGINATION
+	This request is paginated, the cursors of the returned page are read from the CB-BEFORE and CB-AFTER headers.
+*/
+func GetAccountHolds(client *Client, account_id string) (AccountHoldsResponse, error) {
+	output, _, err := GetAccountHoldsPageContext(context.Background(), client, account_id, Pagination{})
+	return output, err
+}
+
+func GetAccountHoldsPage(client *Client, account_id string, pagination Pagination) (AccountHoldsResponse, Cursor, error) {
+	return GetAccountHoldsPageContext(context.Background(), client, account_id, pagination)
+}
+
+func GetAccountHoldsPageContext(ctx context.Context, client *Client, account_id string, pagination Pagination) (AccountHoldsResponse, Cursor, error) {
+	params := url.Values{}
+	pagination.apply(params)
+	output := AccountHoldsResponse{}
+	res, err := client.GetContext(ctx, fmt.Sprintf("/accounts/%s/holds", account_id), params, &output)
+	if err != nil {
+		return AccountHoldsResponse{}, Cursor{}, err
+	}
+	return output, cursorFromResponse(res), nil
+}
+
+/*
+	Walk the holds of an account page by page, from the latest towards the oldest.
+	Pages are decoded into a *AccountHoldsResponse.
+*/
+func NewAccountHoldsPaginator(client *Client, account_id string, pagination Pagination) *Paginator {
+	return NewPaginator(client, fmt.Sprintf("/accounts/%s/holds", account_id), url.Values{}, pagination)
+}
+
+/*
+	Totals of the holds of an account, by type
+*/
+type AccountHoldsSummary struct {
+	AccountID string
+	Count     int
+	Total     Decimal
+	Orders    Decimal
+	Transfers Decimal
+}
+
+func (r AccountHoldsResponse) Summarize(account_id string) AccountHoldsSummary {
+	summary := AccountHoldsSummary{AccountID: account_id}
+	for _, hold := range r {
+		summary.add(hold)
+	}
+	return summary
+}
+
+func (s *AccountHoldsSummary) add(hold AccountHold) {
+	s.Count++
+	s.Total = s.Total.Add(hold.Amount)
+	switch hold.Type {
+	case HoldOrder:
+		s.Orders = s.Orders.Add(hold.Amount)
+	case HoldTransfer:
+		s.Transfers = s.Transfers.Add(hold.Amount)
+	}
+}
+
+/*
+	Part of the gap between the balance and the available funds of account that the holds do
+	not explain, 0 when they add up
+*/
+func (s AccountHoldsSummary) Unexplained(account Account) Decimal {
+	return account.Balance.Sub(account.Available).Sub(s.Total)
+}
+
+/*
+	Sum every hold of an account, walking all of the pages
+*/
+func SummarizeAccountHolds(client *Client, account_id string) (*AccountHoldsSummary, error) {
+	return SummarizeAccountHoldsContext(context.Background(), client, account_id)
+}
+
+func SummarizeAccountHoldsContext(ctx context.Context, client *Client, account_id string) (*AccountHoldsSummary, error) {
+	summary := &AccountHoldsSummary{AccountID: account_id}
+	paginator := NewAccountHoldsPaginator(client, account_id, Pagination{Limit: MaxPaginationLimit})
+	err := paginator.ForEach(ctx, &AccountHoldsResponse{}, func(item interface{}) error {
+		summary.add(item.(AccountHold))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
 type AccountReportStatus struct {
 	ID          string    `json:"id"`
 	Type        string    `json:"type"`
@@ -61,17 +542,21 @@ type AccountReportStatus struct {
 	| ready | The report is ready for download from file_url |
 */
 func GetAccountReportStatus(client *Client, report_id string) (*AccountReportStatus, error) {
+	return GetAccountReportStatusContext(context.Background(), client, report_id)
+}
+
+func GetAccountReportStatusContext(ctx context.Context, client *Client, report_id string) (*AccountReportStatus, error) {
 	pathname := fmt.Sprintf("/reports/%s", report_id)
 	params := url.Values{}
 	output := &AccountReportStatus{}
-	_, err := client.Get(pathname, params, &output)
+	_, err := client.GetContext(ctx, pathname, params, &output)
 	return output, err
 }
 
 type AccountTrailingVolume struct {
 	ProductID      string    `json:"product_id"`
-	ExchangeVolume float64   `json:"exchange_volume,string"`
-	Volume         float64   `json:"volume,string"`
+	ExchangeVolume Decimal   `json:"exchange_volume"`
+	Volume         Decimal   `json:"volume"`
 	RecordedAt     time.Time `json:"recorded_at"`
 }
 type AccountTrailingVolumeResponse []AccountTrailingVolume
@@ -102,9 +587,13 @@ Trailing Volume
 ]
 */
 func GetAccountTrailingVolume(client *Client) (AccountTrailingVolumeResponse, error) {
+	return GetAccountTrailingVolumeContext(context.Background(), client)
+}
+
+func GetAccountTrailingVolumeContext(ctx context.Context, client *Client) (AccountTrailingVolumeResponse, error) {
 	pathname := "/users/self/trailing-volume"
 	params := url.Values{}
 	output := []AccountTrailingVolume{}
-	_, err := client.Get(pathname, params, &output)
+	_, err := client.GetContext(ctx, pathname, params, &output)
 	return output, err
 }
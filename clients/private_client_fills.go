@@ -0,0 +1,143 @@
+package clients
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+/*
+	Whether a fill added liquidity to the book (maker) or removed it (taker)
+*/
+type Liquidity string
+
+const (
+	LiquidityMaker Liquidity = "M"
+	LiquidityTaker Liquidity = "T"
+)
+
+type Fill struct {
+	TradeID   int       `json:"trade_id"`
+	ProductID string    `json:"product_id"`
+	OrderID   string    `json:"order_id"`
+	UserID    string    `json:"user_id"`
+	ProfileID string    `json:"profile_id"`
+	Price     Decimal   `json:"price"`
+	Size      Decimal   `json:"size"`
+	Fee       Decimal   `json:"fee"`
+	UsdVolume Decimal   `json:"usd_volume"`
+	Liquidity Liquidity `json:"liquidity"`
+	Side      OrderSide `json:"side"`
+	Settled   bool      `json:"settled"`
+	CreatedAt time.Time `json:"created_at"`
+}
+type FillsResponse []Fill
+
+/*
+	Value of the fill in the quote currency, price * size
+*/
+func (f Fill) Value() Decimal {
+	return f.Price.Mul(f.Size)
+}
+
+/*
+	Fills to list with GetFills, by default the fills of every order and product
+*/
+type FillFilter struct {
+	// Only list the fills of this order
+	OrderID string
+	// Only list the fills of this product
+	ProductID string
+}
+
+func (f FillFilter) params() url.Values {
+	params := url.Values{}
+	if f.OrderID != "" {
+		params.Set("order_id", f.OrderID)
+	}
+	if f.ProductID != "" {
+		params.Set("product_id", f.ProductID)
+	}
+	return params
+}
+
+/*
+	List Fills
+	Get a list of recent fills.
+
+	HTTP REQUEST
+		GET /fills
+
+	QUERY PARAMETERS
+	| Param | Default | Description |
+	| order_id | all | Limit list of fills to this order_id |
+	| product_id | all | Limit list of fills to this product_id |
+
+	HTTP RESPONSE
+	[
+		{
+			"trade_id": 74,
+			"product_id": "BTC-USD",
+			"price": "10.00",
+			"size": "0.01",
+			"order_id": "d50ec984-77a8-460a-b958-66f114b0de9b",
+			"created_at": "2014-11-07T22:19:28.578544Z",
+			"liquidity": "T",
+			"fee": "0.00025",
+			"settled": true,
+			"side": "buy"
+		}
+	]
+
+	SETTLEMENT AND FEES
+	Fees are recorded in two stages. Immediately after the matching engine completes a match, the
+	fill is inserted into our datastore. Once the fill is recorded, a settlement process will
+	settle the fill and credit both trading counterparties.
+
+	The fee field indicates the fees charged for this individual fill.
+
+	LIQUIDITY
+	The liquidity field indicates if the fill was the result of a liquidity provider or liquidity
+	taker. M indicates Maker and T indicates Taker.
+
+	PAGINATION
+	This request is paginated, the cursors of the returned page are read from the CB-BEFORE and CB-AFTER headers.
+*/
+func GetFills(client *Client, filter FillFilter) (FillsResponse, error) {
+	output, _, err := GetFillsPageContext(context.Background(), client, filter, Pagination{})
+	return output, err
+}
+
+func GetFillsPage(client *Client, filter FillFilter, pagination Pagination) (FillsResponse, Cursor, error) {
+	return GetFillsPageContext(context.Background(), client, filter, pagination)
+}
+
+func GetFillsPageContext(ctx context.Context, client *Client, filter FillFilter, pagination Pagination) (FillsResponse, Cursor, error) {
+	params := filter.params()
+	pagination.apply(params)
+	output := FillsResponse{}
+	res, err := client.GetContext(ctx, "/fills", params, &output)
+	if err != nil {
+		return FillsResponse{}, Cursor{}, err
+	}
+	return output, cursorFromResponse(res), nil
+}
+
+/*
+	Walk the fills matching filter page by page, from the latest towards the oldest.
+	Pages are decoded into a *FillsResponse.
+*/
+func NewFillsPaginator(client *Client, filter FillFilter, pagination Pagination) *Paginator {
+	return NewPaginator(client, "/fills", filter.params(), pagination)
+}
+
+/*
+	Call fn with every historical fill matching filter, from the latest towards the oldest.
+	The iteration stops at the first error returned by fn; ErrStopIteration stops it without error.
+*/
+func ForEachFill(ctx context.Context, client *Client, filter FillFilter, fn func(fill Fill) error) error {
+	paginator := NewFillsPaginator(client, filter, Pagination{Limit: MaxPaginationLimit})
+	return paginator.ForEach(ctx, &FillsResponse{}, func(item interface{}) error {
+		return fn(item.(Fill))
+	})
+}
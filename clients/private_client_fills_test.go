@@ -0,0 +1,106 @@
+package clients
+
+import (
+	"context"
+	"gopkg.in/jarcoal/httpmock.v1"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_GetFills(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	order_id := ""
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/fills",
+		func(req *http.Request) (*http.Response, error) {
+			order_id = req.URL.Query().Get("order_id")
+			return httpmock.NewStringResponse(200, `
+				[
+					{
+						"trade_id": 74,
+						"product_id": "BTC-USD",
+						"price": "10.00",
+						"size": "0.01",
+						"order_id": "d50ec984-77a8-460a-b958-66f114b0de9b",
+						"created_at": "2014-11-07T22:19:28.578544Z",
+						"liquidity": "T",
+						"fee": "0.00025",
+						"settled": true,
+						"side": "buy"
+					}
+				]
+			`), nil
+		},
+	)
+	client := NewMockClient()
+	output, err := GetFills(client, FillFilter{OrderID: "d50ec984-77a8-460a-b958-66f114b0de9b"})
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if order_id != "d50ec984-77a8-460a-b958-66f114b0de9b" {
+		t.Fatalf("Expected order_id = d50ec984-77a8-460a-b958-66f114b0de9b, actual = %v", order_id)
+	}
+	if len(output) != 1 {
+		t.Fatalf("Expected output.length = 1, actual = %v", len(output))
+	}
+	fill := output[0]
+	if fill.TradeID != 74 || fill.Liquidity != LiquidityTaker || fill.Side != OrderSideBuy || !fill.Settled {
+		t.Fatalf("Expected a settled taker buy, actual = %v", fill)
+	}
+	if !fill.Fee.Equal(MustDecimal("0.00025")) || fill.Value().String() != "0.1000" {
+		t.Fatalf("Expected fee 0.00025 on a value of 0.1000, actual = %v and %v", fill.Fee, fill.Value())
+	}
+	if !fill.CreatedAt.Equal(time.Date(2014, 11, 7, 22, 19, 28, 578544000, time.UTC)) {
+		t.Fatalf("Expected created_at = 2014-11-07T22:19:28.578544Z, actual = %v", fill.CreatedAt)
+	}
+}
+
+func Test_ForEachFill(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/fills",
+		func(req *http.Request) (*http.Response, error) {
+			if req.URL.Query().Get("product_id") != "ETH-USD" {
+				return httpmock.NewStringResponse(400, `{ "message": "Invalid product_id" }`), nil
+			}
+			switch req.URL.Query().Get("after") {
+			case "":
+				res := httpmock.NewStringResponse(200, `[
+					{ "trade_id": 3, "product_id": "ETH-USD", "price": "300", "size": "1", "fee": "0.9", "liquidity": "T", "side": "sell" },
+					{ "trade_id": 2, "product_id": "ETH-USD", "price": "290", "size": "1", "fee": "0", "liquidity": "M", "side": "buy" }
+				]`)
+				res.Header.Set("CB-AFTER", "2")
+				return res, nil
+			case "2":
+				return httpmock.NewStringResponse(200, `[
+					{ "trade_id": 1, "product_id": "ETH-USD", "price": "280", "size": "2", "fee": "0", "liquidity": "M", "side": "buy" }
+				]`), nil
+			}
+			return httpmock.NewStringResponse(200, `[]`), nil
+		},
+	)
+	client := NewMockClient()
+	trade_ids := []int{}
+	fees := Decimal{}
+	err := ForEachFill(context.Background(), client, FillFilter{ProductID: "ETH-USD"}, func(fill Fill) error {
+		trade_ids = append(trade_ids, fill.TradeID)
+		fees = fees.Add(fill.Fee)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if len(trade_ids) != 3 || trade_ids[0] != 3 || trade_ids[2] != 1 {
+		t.Fatalf("Expected trade_ids = [3 2 1], actual = %v", trade_ids)
+	}
+	if !fees.Equal(MustDecimal("0.9")) {
+		t.Fatalf("Expected fees = 0.9, actual = %v", fees)
+	}
+}
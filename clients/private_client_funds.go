@@ -0,0 +1,371 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+/*
+	A deposit or withdrawal. PayoutAt is only set for payment method transfers, which are not
+	instant.
+*/
+type Transfer struct {
+	ID       string    `json:"id"`
+	Amount   Decimal   `json:"amount"`
+	Currency string    `json:"currency"`
+	PayoutAt time.Time `json:"payout_at"`
+}
+
+type MoneyAmount struct {
+	Amount   Decimal `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+type PaymentMethodLimit struct {
+	PeriodInDays int         `json:"period_in_days"`
+	Total        MoneyAmount `json:"total"`
+	Remaining    MoneyAmount `json:"remaining"`
+}
+
+type PaymentMethod struct {
+	ID            string `json:"id"`
+	Type          string `json:"type"`
+	Name          string `json:"name"`
+	Currency      string `json:"currency"`
+	PrimaryBuy    bool   `json:"primary_buy"`
+	PrimarySell   bool   `json:"primary_sell"`
+	AllowBuy      bool   `json:"allow_buy"`
+	AllowSell     bool   `json:"allow_sell"`
+	AllowDeposit  bool   `json:"allow_deposit"`
+	AllowWithdraw bool   `json:"allow_withdraw"`
+	// Limits by kind of transfer, e.g. "buy", "instant_buy", "sell" or "deposit"
+	Limits map[string][]PaymentMethodLimit `json:"limits"`
+}
+type PaymentMethodsResponse []PaymentMethod
+
+type CoinbaseAccount struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Balance  Decimal `json:"balance"`
+	Currency string  `json:"currency"`
+	Type     string  `json:"type"`
+	Primary  bool    `json:"primary"`
+	Active   bool    `json:"active"`
+}
+type CoinbaseAccountsResponse []CoinbaseAccount
+
+type CryptoAddress struct {
+	ID        string    `json:"id"`
+	Address   string    `json:"address"`
+	Name      string    `json:"name"`
+	Network   string    `json:"network"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+/*
+	Body of the deposit and withdrawal requests, exactly one of the destination ids is set
+*/
+type transferRequest struct {
+	Amount            Decimal `json:"amount"`
+	Currency          string  `json:"currency"`
+	PaymentMethodID   string  `json:"payment_method_id,omitempty"`
+	CoinbaseAccountID string  `json:"coinbase_account_id,omitempty"`
+	CryptoAddress     string  `json:"crypto_address,omitempty"`
+}
+
+func (r transferRequest) validate(destination string) error {
+	if r.Amount.Sign() <= 0 {
+		return fmt.Errorf("clients: invalid transfer amount %v, must be greater than 0", r.Amount)
+	}
+	if r.Currency == "" {
+		return fmt.Errorf("clients: invalid transfer, currency is required")
+	}
+	if destination == "" {
+		return fmt.Errorf("clients: invalid transfer, no payment method, Coinbase account or crypto address")
+	}
+	return nil
+}
+
+func postTransferContext(ctx context.Context, client *Client, pathname string, request transferRequest, destination string) (*Transfer, error) {
+	if err := request.validate(destination); err != nil {
+		return nil, err
+	}
+	output := &Transfer{}
+	_, err := client.PostContext(ctx, pathname, request, output)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+/*
+	Deposit: Payment method
+	Deposit funds from a payment method. See GetPaymentMethods to retrieve details of your
+	payment methods.
+
+	HTTP REQUEST
+		POST /deposits/payment-method
+
+	PARAMETERS
+	| Param | Description |
+	| amount | The amount to deposit |
+	| currency | The type of currency |
+	| payment_method_id | ID of the payment method |
+
+	HTTP RESPONSE
+	{
+		"id": "593533d2-ff31-46e0-b22e-ca754147a96a",
+		"amount": "10.00",
+		"currency": "USD",
+		"payout_at": "2016-08-20T00:31:09Z"
+	}
+*/
+func DepositFromPaymentMethod(client *Client, amount Decimal, currency, payment_method_id string) (*Transfer, error) {
+	return DepositFromPaymentMethodContext(context.Background(), client, amount, currency, payment_method_id)
+}
+
+func DepositFromPaymentMethodContext(ctx context.Context, client *Client, amount Decimal, currency, payment_method_id string) (*Transfer, error) {
+	request := transferRequest{Amount: amount, Currency: currency, PaymentMethodID: payment_method_id}
+	return postTransferContext(ctx, client, "/deposits/payment-method", request, payment_method_id)
+}
+
+/*
+	Deposit: Coinbase
+	Deposit funds from a Coinbase account. You can move funds between your Coinbase accounts and
+	your GDAX trading accounts within your daily limits. Moving funds between Coinbase and GDAX is
+	instant and free. See GetCoinbaseAccounts to retrieve your Coinbase accounts.
+
+	HTTP REQUEST
+		POST /deposits/coinbase-account
+
+	PARAMETERS
+	| Param | Description |
+	| amount | The amount to deposit |
+	| currency | The type of currency |
+	| coinbase_account_id | ID of the coinbase account |
+
+	HTTP RESPONSE
+	{
+		"id": "593533d2-ff31-46e0-b22e-ca754147a96a",
+		"amount": "10.00",
+		"currency": "BTC"
+	}
+*/
+func DepositFromCoinbaseAccount(client *Client, amount Decimal, currency, coinbase_account_id string) (*Transfer, error) {
+	return DepositFromCoinbaseAccountContext(context.Background(), client, amount, currency, coinbase_account_id)
+}
+
+func DepositFromCoinbaseAccountContext(ctx context.Context, client *Client, amount Decimal, currency, coinbase_account_id string) (*Transfer, error) {
+	request := transferRequest{Amount: amount, Currency: currency, CoinbaseAccountID: coinbase_account_id}
+	return postTransferContext(ctx, client, "/deposits/coinbase-account", request, coinbase_account_id)
+}
+
+/*
+	Withdraw: Payment method
+	Withdraw funds to a payment method. See GetPaymentMethods to retrieve details of your
+	payment methods.
+
+	HTTP REQUEST
+		POST /withdrawals/payment-method
+
+	PARAMETERS
+	| Param | Description |
+	| amount | The amount to withdraw |
+	| currency | The type of currency |
+	| payment_method_id | ID of the payment method |
+
+	HTTP RESPONSE
+	{
+		"id": "593533d2-ff31-46e0-b22e-ca754147a96a",
+		"amount": "10.00",
+		"currency": "USD",
+		"payout_at": "2016-08-20T00:31:09Z"
+	}
+*/
+func WithdrawToPaymentMethod(client *Client, amount Decimal, currency, payment_method_id string) (*Transfer, error) {
+	return WithdrawToPaymentMethodContext(context.Background(), client, amount, currency, payment_method_id)
+}
+
+func WithdrawToPaymentMethodContext(ctx context.Context, client *Client, amount Decimal, currency, payment_method_id string) (*Transfer, error) {
+	request := transferRequest{Amount: amount, Currency: currency, PaymentMethodID: payment_method_id}
+	return postTransferContext(ctx, client, "/withdrawals/payment-method", request, payment_method_id)
+}
+
+/*
+	Withdraw: Coinbase
+	Withdraw funds to a Coinbase account. You can move funds between your Coinbase accounts and
+	your GDAX trading accounts within your daily limits.
+
+	HTTP REQUEST
+		POST /withdrawals/coinbase-account
+
+	PARAMETERS
+	| Param | Description |
+	| amount | The amount to withdraw |
+	| currency | The type of currency |
+	| coinbase_account_id | ID of the coinbase account |
+
+	HTTP RESPONSE
+	{
+		"id": "593533d2-ff31-46e0-b22e-ca754147a96a",
+		"amount": "10.00",
+		"currency": "BTC"
+	}
+*/
+func WithdrawToCoinbaseAccount(client *Client, amount Decimal, currency, coinbase_account_id string) (*Transfer, error) {
+	return WithdrawToCoinbaseAccountContext(context.Background(), client, amount, currency, coinbase_account_id)
+}
+
+func WithdrawToCoinbaseAccountContext(ctx context.Context, client *Client, amount Decimal, currency, coinbase_account_id string) (*Transfer, error) {
+	request := transferRequest{Amount: amount, Currency: currency, CoinbaseAccountID: coinbase_account_id}
+	return postTransferContext(ctx, client, "/withdrawals/coinbase-account", request, coinbase_account_id)
+}
+
+/*
+	Withdraw: Crypto
+	Withdraws funds to a crypto address.
+
+	HTTP REQUEST
+		POST /withdrawals/crypto
+
+	PARAMETERS
+	| Param | Description |
+	| amount | The amount to withdraw |
+	| currency | The type of currency |
+	| crypto_address | A crypto address of the recipient |
+
+	HTTP RESPONSE
+	{
+		"id": "593533d2-ff31-46e0-b22e-ca754147a96a",
+		"amount": "10.00",
+		"currency": "BTC"
+	}
+*/
+func WithdrawToCryptoAddress(client *Client, amount Decimal, currency, crypto_address string) (*Transfer, error) {
+	return WithdrawToCryptoAddressContext(context.Background(), client, amount, currency, crypto_address)
+}
+
+func WithdrawToCryptoAddressContext(ctx context.Context, client *Client, amount Decimal, currency, crypto_address string) (*Transfer, error) {
+	request := transferRequest{Amount: amount, Currency: currency, CryptoAddress: crypto_address}
+	return postTransferContext(ctx, client, "/withdrawals/crypto", request, crypto_address)
+}
+
+/*
+	Generate a crypto deposit address for a Coinbase account, funds sent to it are credited to
+	that account and can then be moved with DepositFromCoinbaseAccount.
+
+	HTTP REQUEST
+		POST /coinbase-accounts/:coinbase_account_id/addresses
+
+	HTTP RESPONSE
+	{
+		"id": "fc9fed1e-d25b-54d8-b52b-7fa250c9ae2d",
+		"address": "0x0c4b8e9e1c1ab4b8b3bd0a3a3fa5a5b0c2d3e4f5",
+		"name": "New exchange deposit address",
+		"network": "ethereum",
+		"created_at": "2017-11-20T20:03:31Z",
+		"updated_at": "2017-11-20T20:03:31Z"
+	}
+*/
+func GenerateCryptoAddress(client *Client, coinbase_account_id string) (*CryptoAddress, error) {
+	return GenerateCryptoAddressContext(context.Background(), client, coinbase_account_id)
+}
+
+func GenerateCryptoAddressContext(ctx context.Context, client *Client, coinbase_account_id string) (*CryptoAddress, error) {
+	output := &CryptoAddress{}
+	_, err := client.PostContext(ctx, fmt.Sprintf("/coinbase-accounts/%s/addresses", coinbase_account_id), nil, output)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+/*
+	Payment Methods
+	Get a list of your payment methods.
+
+	HTTP REQUEST
+		GET /payment-methods
+
+	HTTP RESPONSE
+	[
+		{
+			"id": "bc6d7162-d984-5ffa-963c-a493b1c1370b",
+			"type": "ach_bank_account",
+			"name": "Bank of America - eBan... ********7134",
+			"currency": "USD",
+			"primary_buy": true,
+			"primary_sell": true,
+			"allow_buy": true,
+			"allow_sell": true,
+			"allow_deposit": true,
+			"allow_withdraw": true,
+			"limits": {
+				"buy": [
+					{
+						"period_in_days": 1,
+						"total": { "amount": "10000.00", "currency": "USD" },
+						"remaining": { "amount": "10000.00", "currency": "USD" }
+					}
+				]
+			}
+		}
+	]
+*/
+func GetPaymentMethods(client *Client) (PaymentMethodsResponse, error) {
+	return GetPaymentMethodsContext(context.Background(), client)
+}
+
+func GetPaymentMethodsContext(ctx context.Context, client *Client) (PaymentMethodsResponse, error) {
+	output := PaymentMethodsResponse{}
+	_, err := client.GetContext(ctx, "/payment-methods", url.Values{}, &output)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+/*
+	List Coinbase accounts
+	Get a list of your coinbase accounts.
+
+	HTTP REQUEST
+		GET /coinbase-accounts
+
+	HTTP RESPONSE
+	[
+		{
+			"id": "fc3a8a57-7142-542d-8436-95a3d82e1622",
+			"name": "ETH Wallet",
+			"balance": "0.00000000",
+			"currency": "ETH",
+			"type": "wallet",
+			"primary": false,
+			"active": true
+		},
+		{
+			"id": "2ae3354e-f1c3-5771-8a37-6228e9d239db",
+			"name": "USD Wallet",
+			"balance": "0.00",
+			"currency": "USD",
+			"type": "fiat",
+			"primary": false,
+			"active": true
+		}
+	]
+*/
+func GetCoinbaseAccounts(client *Client) (CoinbaseAccountsResponse, error) {
+	return GetCoinbaseAccountsContext(context.Background(), client)
+}
+
+func GetCoinbaseAccountsContext(ctx context.Context, client *Client) (CoinbaseAccountsResponse, error) {
+	output := CoinbaseAccountsResponse{}
+	_, err := client.GetContext(ctx, "/coinbase-accounts", url.Values{}, &output)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
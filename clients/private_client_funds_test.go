@@ -0,0 +1,209 @@
+package clients
+
+import (
+	"encoding/json"
+	"gopkg.in/jarcoal/httpmock.v1"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_Transfers(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	bodies := map[string]map[string]string{}
+	for _, pathname := range []string{"/deposits/payment-method", "/deposits/coinbase-account", "/withdrawals/payment-method", "/withdrawals/coinbase-account", "/withdrawals/crypto"} {
+		pathname := pathname
+		httpmock.RegisterResponder(
+			"POST",
+			"https://mock-api.gdax.com"+pathname,
+			func(req *http.Request) (*http.Response, error) {
+				body := map[string]string{}
+				data, _ := ioutil.ReadAll(req.Body)
+				json.Unmarshal(data, &body)
+				bodies[pathname] = body
+				return httpmock.NewStringResponse(200, `
+					{
+						"id": "593533d2-ff31-46e0-b22e-ca754147a96a",
+						"amount": "`+body["amount"]+`",
+						"currency": "`+body["currency"]+`",
+						"payout_at": "2016-08-20T00:31:09Z"
+					}
+				`), nil
+			},
+		)
+	}
+	client := NewMockClient()
+
+	transfer, err := DepositFromPaymentMethod(client, MustDecimal("10.00"), "USD", "bc6d7162-d984-5ffa-963c-a493b1c1370b")
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if transfer.ID != "593533d2-ff31-46e0-b22e-ca754147a96a" || transfer.Amount.String() != "10.00" || transfer.Currency != "USD" {
+		t.Fatalf("Expected a 10.00 USD transfer, actual = %v", transfer)
+	}
+	if !transfer.PayoutAt.Equal(time.Date(2016, 8, 20, 0, 31, 9, 0, time.UTC)) {
+		t.Fatalf("Expected payout_at = 2016-08-20T00:31:09Z, actual = %v", transfer.PayoutAt)
+	}
+	if _, err := DepositFromCoinbaseAccount(client, MustDecimal("1.5"), "BTC", "fc3a8a57-7142-542d-8436-95a3d82e1622"); err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if _, err := WithdrawToPaymentMethod(client, MustDecimal("20"), "USD", "bc6d7162-d984-5ffa-963c-a493b1c1370b"); err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if _, err := WithdrawToCoinbaseAccount(client, MustDecimal("0.1"), "ETH", "fc3a8a57-7142-542d-8436-95a3d82e1622"); err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if _, err := WithdrawToCryptoAddress(client, MustDecimal("0.01"), "BTC", "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"); err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+
+	expected := map[string]map[string]string{
+		"/deposits/payment-method":      {"amount": "10.00", "currency": "USD", "payment_method_id": "bc6d7162-d984-5ffa-963c-a493b1c1370b"},
+		"/deposits/coinbase-account":    {"amount": "1.5", "currency": "BTC", "coinbase_account_id": "fc3a8a57-7142-542d-8436-95a3d82e1622"},
+		"/withdrawals/payment-method":   {"amount": "20", "currency": "USD", "payment_method_id": "bc6d7162-d984-5ffa-963c-a493b1c1370b"},
+		"/withdrawals/coinbase-account": {"amount": "0.1", "currency": "ETH", "coinbase_account_id": "fc3a8a57-7142-542d-8436-95a3d82e1622"},
+		"/withdrawals/crypto":           {"amount": "0.01", "currency": "BTC", "crypto_address": "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"},
+	}
+	for pathname, body := range expected {
+		actual := bodies[pathname]
+		if len(actual) != len(body) {
+			t.Fatalf("Expected %v body = %v, actual = %v", pathname, body, actual)
+		}
+		for key, value := range body {
+			if actual[key] != value {
+				t.Fatalf("Expected %v body = %v, actual = %v", pathname, body, actual)
+			}
+		}
+	}
+}
+
+func Test_Transfers_Validation(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	client := NewMockClient()
+	if _, err := WithdrawToCryptoAddress(client, MustDecimal("0"), "BTC", "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"); err == nil {
+		t.Fatalf("Expected an error for a zero amount")
+	}
+	if _, err := WithdrawToCryptoAddress(client, MustDecimal("1"), "", "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"); err == nil {
+		t.Fatalf("Expected an error without currency")
+	}
+	if _, err := DepositFromCoinbaseAccount(client, MustDecimal("1"), "BTC", ""); err == nil {
+		t.Fatalf("Expected an error without Coinbase account")
+	}
+}
+
+func Test_GetPaymentMethods(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/payment-methods",
+		httpmock.NewStringResponder(
+			200,
+			`
+				[
+					{
+						"id": "bc6d7162-d984-5ffa-963c-a493b1c1370b",
+						"type": "ach_bank_account",
+						"name": "Bank of America - eBan... ********7134",
+						"currency": "USD",
+						"primary_buy": true,
+						"primary_sell": true,
+						"allow_buy": true,
+						"allow_sell": true,
+						"allow_deposit": true,
+						"allow_withdraw": true,
+						"limits": {
+							"buy": [
+								{
+									"period_in_days": 1,
+									"total": { "amount": "10000.00", "currency": "USD" },
+									"remaining": { "amount": "9000.00", "currency": "USD" }
+								}
+							]
+						}
+					}
+				]
+			`,
+		),
+	)
+	client := NewMockClient()
+	output, err := GetPaymentMethods(client)
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if len(output) != 1 {
+		t.Fatalf("Expected output.length = 1, actual = %v", len(output))
+	}
+	method := output[0]
+	if method.Type != "ach_bank_account" || !method.AllowDeposit || !method.AllowWithdraw {
+		t.Fatalf("Expected a bank account allowing deposits and withdrawals, actual = %v", method)
+	}
+	limits := method.Limits["buy"]
+	if len(limits) != 1 || limits[0].PeriodInDays != 1 || limits[0].Remaining.Amount.String() != "9000.00" {
+		t.Fatalf("Expected a daily buy limit with 9000.00 remaining, actual = %v", limits)
+	}
+}
+
+func Test_GetCoinbaseAccounts(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/coinbase-accounts",
+		httpmock.NewStringResponder(
+			200,
+			`
+				[
+					{
+						"id": "fc3a8a57-7142-542d-8436-95a3d82e1622",
+						"name": "ETH Wallet",
+						"balance": "0.00000000",
+						"currency": "ETH",
+						"type": "wallet",
+						"primary": false,
+						"active": true
+					}
+				]
+			`,
+		),
+	)
+	httpmock.RegisterResponder(
+		"POST",
+		"https://mock-api.gdax.com/coinbase-accounts/fc3a8a57-7142-542d-8436-95a3d82e1622/addresses",
+		httpmock.NewStringResponder(
+			200,
+			`
+				{
+					"id": "fc9fed1e-d25b-54d8-b52b-7fa250c9ae2d",
+					"address": "0x0c4b8e9e1c1ab4b8b3bd0a3a3fa5a5b0c2d3e4f5",
+					"name": "New exchange deposit address",
+					"network": "ethereum",
+					"created_at": "2017-11-20T20:03:31Z",
+					"updated_at": "2017-11-20T20:03:31Z"
+				}
+			`,
+		),
+	)
+	client := NewMockClient()
+	output, err := GetCoinbaseAccounts(client)
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if len(output) != 1 || output[0].Currency != "ETH" || !output[0].Active || !output[0].Balance.IsZero() {
+		t.Fatalf("Expected an empty active ETH wallet, actual = %v", output)
+	}
+	address, err := GenerateCryptoAddress(client, output[0].ID)
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if address.Address != "0x0c4b8e9e1c1ab4b8b3bd0a3a3fa5a5b0c2d3e4f5" || address.Network != "ethereum" {
+		t.Fatalf("Expected an ethereum address, actual = %v", address)
+	}
+}
@@ -0,0 +1,659 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+type OrderSide string
+
+const (
+	OrderSideBuy  OrderSide = "buy"
+	OrderSideSell OrderSide = "sell"
+)
+
+type OrderType string
+
+const (
+	OrderTypeLimit  OrderType = "limit"
+	OrderTypeMarket OrderType = "market"
+	OrderTypeStop   OrderType = "stop"
+)
+
+/*
+	How long a limit order remains active before being executed or expiring
+*/
+type TimeInForce string
+
+const (
+	// Good till canceled, the default
+	TimeInForceGTC TimeInForce = "GTC"
+	// Good till time, canceled after CancelAfter
+	TimeInForceGTT TimeInForce = "GTT"
+	// Immediate or cancel, whatever cannot be filled immediately is canceled
+	TimeInForceIOC TimeInForce = "IOC"
+	// Fill or kill, canceled unless it can be filled entirely and immediately
+	TimeInForceFOK TimeInForce = "FOK"
+)
+
+/*
+	Lifetime of a GTT order
+*/
+type CancelAfter string
+
+const (
+	CancelAfterMinute CancelAfter = "min"
+	CancelAfterHour   CancelAfter = "hour"
+	CancelAfterDay    CancelAfter = "day"
+)
+
+/*
+	What happens when an order would match another order of the same user
+*/
+type SelfTradePrevention string
+
+const (
+	// Decrease and Cancel, the default
+	SelfTradeDecrementAndCancel SelfTradePrevention = "dc"
+	// Cancel oldest
+	SelfTradeCancelOldest SelfTradePrevention = "co"
+	// Cancel newest
+	SelfTradeCancelNewest SelfTradePrevention = "cn"
+	// Cancel both
+	SelfTradeCancelBoth SelfTradePrevention = "cb"
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusPending  OrderStatus = "pending"
+	OrderStatusOpen     OrderStatus = "open"
+	OrderStatusActive   OrderStatus = "active"
+	OrderStatusDone     OrderStatus = "done"
+	OrderStatusRejected OrderStatus = "rejected"
+	// Filter matching orders of any status, see OrderFilter
+	OrderStatusAll OrderStatus = "all"
+)
+
+/*
+	Body of POST /orders, built with NewLimitOrder, NewMarketOrder, NewMarketOrderFunds,
+	NewStopOrder or NewStopOrderFunds and refined with the With* methods:
+
+		order := NewLimitOrder(OrderSideBuy, "BTC-USD", MustDecimal("100.00"), MustDecimal("0.01")).
+			WithTimeInForce(TimeInForceGTT).
+			WithCancelAfter(CancelAfterHour).
+			WithPostOnly()
+
+	Price, Size and Funds are pointers so that unset amounts are left out of the request.
+*/
+type OrderRequest struct {
+	ClientOID   string              `json:"client_oid,omitempty"`
+	Type        OrderType           `json:"type"`
+	Side        OrderSide           `json:"side"`
+	ProductID   string              `json:"product_id"`
+	STP         SelfTradePrevention `json:"stp,omitempty"`
+	Price       *Decimal            `json:"price,omitempty"`
+	Size        *Decimal            `json:"size,omitempty"`
+	Funds       *Decimal            `json:"funds,omitempty"`
+	TimeInForce TimeInForce         `json:"time_in_force,omitempty"`
+	CancelAfter CancelAfter         `json:"cancel_after,omitempty"`
+	PostOnly    bool                `json:"post_only,omitempty"`
+}
+
+/*
+	Limit order to buy or sell size at price or better
+*/
+func NewLimitOrder(side OrderSide, product_id string, price, size Decimal) *OrderRequest {
+	return &OrderRequest{Type: OrderTypeLimit, Side: side, ProductID: product_id, Price: &price, Size: &size}
+}
+
+/*
+	Market order to buy or sell size of the base currency
+*/
+func NewMarketOrder(side OrderSide, product_id string, size Decimal) *OrderRequest {
+	return &OrderRequest{Type: OrderTypeMarket, Side: side, ProductID: product_id, Size: &size}
+}
+
+/*
+	Market order to buy or sell for funds of the quote currency
+*/
+func NewMarketOrderFunds(side OrderSide, product_id string, funds Decimal) *OrderRequest {
+	return &OrderRequest{Type: OrderTypeMarket, Side: side, ProductID: product_id, Funds: &funds}
+}
+
+/*
+	Stop order for size of the base currency, turned into a market order once the last trade
+	price reaches the stop price
+*/
+func NewStopOrder(side OrderSide, product_id string, price, size Decimal) *OrderRequest {
+	return &OrderRequest{Type: OrderTypeStop, Side: side, ProductID: product_id, Price: &price, Size: &size}
+}
+
+/*
+	Stop order for funds of the quote currency, turned into a market order once the last trade
+	price reaches the stop price
+*/
+func NewStopOrderFunds(side OrderSide, product_id string, price, funds Decimal) *OrderRequest {
+	return &OrderRequest{Type: OrderTypeStop, Side: side, ProductID: product_id, Price: &price, Funds: &funds}
+}
+
+/*
+	Order id chosen by the client, it must be a UUID and is echoed in the websocket feed
+*/
+func (o *OrderRequest) WithClientOID(client_oid string) *OrderRequest {
+	o.ClientOID = client_oid
+	return o
+}
+
+func (o *OrderRequest) WithSelfTradePrevention(stp SelfTradePrevention) *OrderRequest {
+	o.STP = stp
+	return o
+}
+
+func (o *OrderRequest) WithTimeInForce(time_in_force TimeInForce) *OrderRequest {
+	o.TimeInForce = time_in_force
+	return o
+}
+
+/*
+	Cancel the order after the given time, which makes it a GTT order
+*/
+func (o *OrderRequest) WithCancelAfter(cancel_after CancelAfter) *OrderRequest {
+	o.TimeInForce = TimeInForceGTT
+	o.CancelAfter = cancel_after
+	return o
+}
+
+/*
+	Only make liquidity, the order is rejected instead of taking liquidity
+*/
+func (o *OrderRequest) WithPostOnly() *OrderRequest {
+	o.PostOnly = true
+	return o
+}
+
+/*
+	Returned by Validate for an order the exchange would reject
+*/
+type InvalidOrderError struct {
+	Field  string
+	Reason string
+}
+
+func (e InvalidOrderError) Error() string {
+	return fmt.Sprintf("clients: invalid order %s: %s", e.Field, e.Reason)
+}
+
+func invalidOrder(field, reason string) error {
+	return InvalidOrderError{Field: field, Reason: reason}
+}
+
+/*
+	Check the order before it is sent: required fields, positive amounts and the combinations
+	of fields that are mutually exclusive
+*/
+func (o *OrderRequest) Validate() error {
+	if o.Side != OrderSideBuy && o.Side != OrderSideSell {
+		return invalidOrder("side", fmt.Sprintf("expected buy or sell, got %q", o.Side))
+	}
+	if o.ProductID == "" {
+		return invalidOrder("product_id", "is required")
+	}
+	if o.ClientOID != "" && !isUUID(o.ClientOID) {
+		return invalidOrder("client_oid", fmt.Sprintf("expected a UUID, got %q", o.ClientOID))
+	}
+	switch o.STP {
+	case "", SelfTradeDecrementAndCancel, SelfTradeCancelOldest, SelfTradeCancelNewest, SelfTradeCancelBoth:
+	default:
+		return invalidOrder("stp", fmt.Sprintf("expected dc, co, cn or cb, got %q", o.STP))
+	}
+	for _, amount := range []struct {
+		field string
+		value *Decimal
+	}{{"price", o.Price}, {"size", o.Size}, {"funds", o.Funds}} {
+		if amount.value != nil && amount.value.Sign() <= 0 {
+			return invalidOrder(amount.field, "must be greater than 0")
+		}
+	}
+	switch o.Type {
+	case OrderTypeLimit:
+		return o.validateLimit()
+	case OrderTypeMarket, OrderTypeStop:
+		return o.validateMarket()
+	default:
+		return invalidOrder("type", fmt.Sprintf("expected limit, market or stop, got %q", o.Type))
+	}
+}
+
+func (o *OrderRequest) validateLimit() error {
+	if o.Price == nil {
+		return invalidOrder("price", "is required for a limit order")
+	}
+	if o.Size == nil {
+		return invalidOrder("size", "is required for a limit order")
+	}
+	if o.Funds != nil {
+		return invalidOrder("funds", "is not allowed for a limit order")
+	}
+	switch o.TimeInForce {
+	case "", TimeInForceGTC:
+		if o.CancelAfter != "" {
+			return invalidOrder("cancel_after", "requires time_in_force GTT")
+		}
+	case TimeInForceGTT:
+		switch o.CancelAfter {
+		case CancelAfterMinute, CancelAfterHour, CancelAfterDay:
+		case "":
+			return invalidOrder("cancel_after", "is required for time_in_force GTT")
+		default:
+			return invalidOrder("cancel_after", fmt.Sprintf("expected min, hour or day, got %q", o.CancelAfter))
+		}
+	case TimeInForceIOC, TimeInForceFOK:
+		if o.CancelAfter != "" {
+			return invalidOrder("cancel_after", "requires time_in_force GTT")
+		}
+		if o.PostOnly {
+			return invalidOrder("post_only", fmt.Sprintf("is not allowed with time_in_force %s", o.TimeInForce))
+		}
+	default:
+		return invalidOrder("time_in_force", fmt.Sprintf("expected GTC, GTT, IOC or FOK, got %q", o.TimeInForce))
+	}
+	return nil
+}
+
+func (o *OrderRequest) validateMarket() error {
+	if o.Type == OrderTypeStop && o.Price == nil {
+		return invalidOrder("price", "is required for a stop order")
+	}
+	if o.Type == OrderTypeMarket && o.Price != nil {
+		return invalidOrder("price", "is not allowed for a market order")
+	}
+	if (o.Size == nil) == (o.Funds == nil) {
+		return invalidOrder("size", fmt.Sprintf("exactly one of size or funds is required for a %s order", o.Type))
+	}
+	if o.TimeInForce != "" {
+		return invalidOrder("time_in_force", fmt.Sprintf("is not allowed for a %s order", o.Type))
+	}
+	if o.CancelAfter != "" {
+		return invalidOrder("cancel_after", fmt.Sprintf("is not allowed for a %s order", o.Type))
+	}
+	if o.PostOnly {
+		return invalidOrder("post_only", fmt.Sprintf("is not allowed for a %s order", o.Type))
+	}
+	return nil
+}
+
+/*
+	Whether s looks like 8-4-4-4-12 hexadecimal digits
+*/
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch {
+		case i == 8 || i == 13 || i == 18 || i == 23:
+			if s[i] != '-' {
+				return false
+			}
+		case '0' <= s[i] && s[i] <= '9', 'a' <= s[i] && s[i] <= 'f', 'A' <= s[i] && s[i] <= 'F':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+type Order struct {
+	ID             string              `json:"id"`
+	ClientOID      string              `json:"client_oid"`
+	Price          Decimal             `json:"price"`
+	Size           Decimal             `json:"size"`
+	Funds          Decimal             `json:"funds"`
+	SpecifiedFunds Decimal             `json:"specified_funds"`
+	ProductID      string              `json:"product_id"`
+	Side           OrderSide           `json:"side"`
+	Type           OrderType           `json:"type"`
+	STP            SelfTradePrevention `json:"stp"`
+	TimeInForce    TimeInForce         `json:"time_in_force"`
+	PostOnly       bool                `json:"post_only"`
+	CreatedAt      time.Time           `json:"created_at"`
+	ExpireTime     time.Time           `json:"expire_time"`
+	DoneAt         time.Time           `json:"done_at"`
+	DoneReason     string              `json:"done_reason"`
+	RejectReason   string              `json:"reject_reason"`
+	FillFees       Decimal             `json:"fill_fees"`
+	FilledSize     Decimal             `json:"filled_size"`
+	ExecutedValue  Decimal             `json:"executed_value"`
+	Status         OrderStatus         `json:"status"`
+	Settled        bool                `json:"settled"`
+}
+
+/*
+	Place a New Order
+	You can place different types of orders: limit, market, and stop. Orders can only be placed
+	if your account has sufficient funds. Once an order is placed, your account funds will be put
+	on hold for the duration of the order.
+
+	HTTP REQUEST
+		POST /orders
+
+	PARAMETERS
+	| Param | Description |
+	| client_oid | [optional] Order ID selected by you to identify your order |
+	| type | [optional] limit, market, or stop (default is limit) |
+	| side | buy or sell |
+	| product_id | A valid product id |
+	| stp | [optional] Self-trade prevention flag |
+
+	LIMIT ORDER PARAMETERS
+	| Param | Description |
+	| price | Price per bitcoin |
+	| size | Amount of BTC to buy or sell |
+	| time_in_force | [optional] GTC, GTT, IOC, or FOK (default is GTC) |
+	| cancel_after | [optional]* min, hour, day |
+	| post_only | [optional]** Post only flag |
+	* Requires time_in_force to be GTT
+	** Invalid when time_in_force is IOC or FOK
+
+	MARKET AND STOP ORDER PARAMETERS
+	| Param | Description |
+	| price | [stop only] Desired price at which the stop order triggers |
+	| size | [optional]* Desired amount in BTC |
+	| funds | [optional]* Desired amount of quote currency to use |
+	* One of size or funds is required.
+
+	HTTP RESPONSE
+	{
+		"id": "d0c5340b-6d6c-49d9-b567-48c4bfca13d2",
+		"price": "0.10000000",
+		"size": "0.01000000",
+		"product_id": "BTC-USD",
+		"side": "buy",
+		"stp": "dc",
+		"type": "limit",
+		"time_in_force": "GTC",
+		"post_only": false,
+		"created_at": "2016-12-08T20:02:28.53864Z",
+		"fill_fees": "0.0000000000000000",
+		"filled_size": "0.00000000",
+		"executed_value": "0.0000000000000000",
+		"status": "pending",
+		"settled": false
+	}
+
+	The order is validated before it is sent, an invalid order returns an InvalidOrderError
+	without making a request.
+*/
+func PlaceOrder(client *Client, order *OrderRequest) (*Order, error) {
+	return PlaceOrderContext(context.Background(), client, order)
+}
+
+func PlaceOrderContext(ctx context.Context, client *Client, order *OrderRequest) (*Order, error) {
+	if err := order.Validate(); err != nil {
+		return nil, err
+	}
+	output := &Order{}
+	_, err := client.PostContext(ctx, "/orders", order, output)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+/*
+	Cancel an Order
+	Cancel a previously placed order.
+
+	HTTP REQUEST
+		DELETE /orders/:order_id
+
+	If the order had no matches during its lifetime its record may be purged. This means the
+	order details will not be available with GET /orders/:order_id.
+
+	An order that was filled or canceled in the meantime fails with an error matched by
+	IsOrderAlreadyDone, an unknown order with one matched by IsOrderNotFound. Both mean that
+	the order is no longer open, which is what a caller canceling on shutdown wants.
+*/
+func CancelOrder(client *Client, order_id string) (string, error) {
+	return CancelOrderContext(context.Background(), client, order_id)
+}
+
+func CancelOrderContext(ctx context.Context, client *Client, order_id string) (string, error) {
+	canceled, err := cancelOrdersContext(ctx, client, fmt.Sprintf("/orders/%s", order_id), url.Values{})
+	if err != nil {
+		return "", err
+	}
+	if len(canceled) == 0 {
+		return order_id, nil
+	}
+	return canceled[0], nil
+}
+
+/*
+	Cancel all
+	With best effort, cancel all open orders. The response is a list of ids of the canceled orders.
+
+	HTTP REQUEST
+		DELETE /orders
+
+	QUERY PARAMETERS
+	| Param | Description |
+	| product_id | [optional] Only cancel orders open for a specific product |
+
+	HTTP RESPONSE
+	[
+		"144c6f8e-713f-4682-8435-5280fbe8b2b4",
+		"debe4907-95dc-442f-af3b-cec12f42ebda",
+		"cf7aceee-7b08-4227-a76c-3858144323ab",
+		"dfc5ae27-cadb-4c0c-beef-8994936fde8a",
+		"34fecfbf-de33-4273-b2c6-baf8e8948be4"
+	]
+*/
+func CancelAllOrders(client *Client) ([]string, error) {
+	return CancelAllOrdersContext(context.Background(), client)
+}
+
+func CancelAllOrdersContext(ctx context.Context, client *Client) ([]string, error) {
+	return cancelOrdersContext(ctx, client, "/orders", url.Values{})
+}
+
+/*
+	Cancel all open orders of a product, see CancelAllOrders
+*/
+func CancelProductOrders(client *Client, product_id string) ([]string, error) {
+	return CancelProductOrdersContext(context.Background(), client, product_id)
+}
+
+func CancelProductOrdersContext(ctx context.Context, client *Client, product_id string) ([]string, error) {
+	params := url.Values{}
+	params.Set("product_id", product_id)
+	return cancelOrdersContext(ctx, client, "/orders", params)
+}
+
+/*
+	Send DELETE pathname with the query params and return the canceled order ids. The exchange answers with a list of
+	ids, a single id or an empty body depending on the endpoint, all of which are accepted.
+*/
+func cancelOrdersContext(ctx context.Context, client *Client, pathname string, params url.Values) ([]string, error) {
+	output := json.RawMessage{}
+	_, err := client.DeleteWithParamsContext(ctx, pathname, params, &output)
+	if err == io.EOF {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	canceled := []string{}
+	if err := json.Unmarshal(output, &canceled); err == nil {
+		return canceled, nil
+	}
+	var order_id string
+	if err := json.Unmarshal(output, &order_id); err != nil {
+		return nil, fmt.Errorf("clients: unexpected cancel response %s", output)
+	}
+	return []string{order_id}, nil
+}
+
+type OrdersResponse []Order
+
+/*
+	Orders to list with GetOrders, by default the open, pending and active orders of every product
+*/
+type OrderFilter struct {
+	// Limit the list to these statuses, OrderStatusAll lists every order
+	Statuses []OrderStatus
+	// Only list the orders of this product
+	ProductID string
+}
+
+func (f OrderFilter) params() url.Values {
+	params := url.Values{}
+	for _, status := range f.Statuses {
+		params.Add("status", string(status))
+	}
+	if f.ProductID != "" {
+		params.Set("product_id", f.ProductID)
+	}
+	return params
+}
+
+/*
+	List Orders
+	List your current open orders. Only open or un-settled orders are returned. As soon as an
+	order is no longer open and settled, it will no longer appear in the default request.
+
+	HTTP REQUEST
+		GET /orders
+
+	QUERY PARAMETERS
+	| Param | Default | Description |
+	| status | [open, pending, active] | Limit list of orders to these statuses. Passing all returns orders of all statuses. |
+	| product_id | | [optional] Only list orders for a specific product |
+
+	To specify multiple statuses, use the status query argument multiple times: /orders?status=done&status=pending.
+
+	HTTP RESPONSE
+	[
+		{
+			"id": "d50ec984-77a8-460a-b958-66f114b0de9b",
+			"size": "3.0",
+			"price": "100.23",
+			"product_id": "BTC-USD",
+			"status": "open",
+			"filled_size": "1.23",
+			"fill_fees": "0.001",
+			"settled": false,
+			"side": "buy",
+			"created_at": "2014-11-14T06:39:55.189376Z"
+		},
+		{
+			"id": "b227e691-365c-470f-a860-a9b4a37dd1d8",
+			"size": "1.0",
+			"price": "100.23",
+			"product_id": "BTC-USD",
+			"status": "open",
+			"filled_size": "0.0",
+			"fill_fees": "0.0",
+			"settled": false,
+			"side": "buy",
+			"created_at": "2014-11-14T06:38:16.123536Z"
+		}
+	]
+
+	PAGINATION
+	This request is paginated, the cursors of the returned page are read from the CB-BEFORE and CB-AFTER headers.
+*/
+func GetOrders(client *Client, filter OrderFilter) (OrdersResponse, error) {
+	output, _, err := GetOrdersPageContext(context.Background(), client, filter, Pagination{})
+	return output, err
+}
+
+func GetOrdersPage(client *Client, filter OrderFilter, pagination Pagination) (OrdersResponse, Cursor, error) {
+	return GetOrdersPageContext(context.Background(), client, filter, pagination)
+}
+
+func GetOrdersPageContext(ctx context.Context, client *Client, filter OrderFilter, pagination Pagination) (OrdersResponse, Cursor, error) {
+	params := filter.params()
+	pagination.apply(params)
+	output := OrdersResponse{}
+	res, err := client.GetContext(ctx, "/orders", params, &output)
+	if err != nil {
+		return OrdersResponse{}, Cursor{}, err
+	}
+	return output, cursorFromResponse(res), nil
+}
+
+/*
+	Walk the orders matching filter page by page, from the latest towards the oldest.
+	Pages are decoded into a *OrdersResponse.
+*/
+func NewOrdersPaginator(client *Client, filter OrderFilter, pagination Pagination) *Paginator {
+	return NewPaginator(client, "/orders", filter.params(), pagination)
+}
+
+/*
+	Get an Order
+	Get a single order by order id.
+
+	HTTP REQUEST
+		GET /orders/:order_id
+
+	HTTP RESPONSE
+	{
+		"id": "68e6a28f-ae28-4788-8d4f-5ab4e5e5ae08",
+		"size": "1.00000000",
+		"product_id": "BTC-USD",
+		"side": "buy",
+		"stp": "dc",
+		"funds": "9.9750623400000000",
+		"specified_funds": "10.0000000000000000",
+		"type": "market",
+		"post_only": false,
+		"created_at": "2016-12-08T20:09:05.508883Z",
+		"done_at": "2016-12-08T20:09:05.527Z",
+		"done_reason": "filled",
+		"fill_fees": "0.0249376391550000",
+		"filled_size": "0.01291771",
+		"executed_value": "9.9750556620000000",
+		"status": "done",
+		"settled": true
+	}
+
+	If the order is canceled the response may have status code 404 if the order had no matches.
+	Open orders may change state between the request and the response depending on market conditions.
+*/
+func GetOrder(client *Client, order_id string) (*Order, error) {
+	return GetOrderContext(context.Background(), client, order_id)
+}
+
+func GetOrderContext(ctx context.Context, client *Client, order_id string) (*Order, error) {
+	return getOrderContext(ctx, client, fmt.Sprintf("/orders/%s", order_id))
+}
+
+/*
+	Get a single order by the client_oid it was placed with.
+
+	HTTP REQUEST
+		GET /orders/client:<client_oid>
+*/
+func GetOrderByClientOID(client *Client, client_oid string) (*Order, error) {
+	return GetOrderByClientOIDContext(context.Background(), client, client_oid)
+}
+
+func GetOrderByClientOIDContext(ctx context.Context, client *Client, client_oid string) (*Order, error) {
+	return getOrderContext(ctx, client, fmt.Sprintf("/orders/client:%s", client_oid))
+}
+
+func getOrderContext(ctx context.Context, client *Client, pathname string) (*Order, error) {
+	output := &Order{}
+	_, err := client.GetContext(ctx, pathname, url.Values{}, output)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
@@ -0,0 +1,403 @@
+package clients
+
+import (
+	"encoding/json"
+	"gopkg.in/jarcoal/httpmock.v1"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func Test_OrderRequest_JSON(t *testing.T) {
+	order := NewLimitOrder(OrderSideBuy, "BTC-USD", MustDecimal("100.00"), MustDecimal("0.01")).
+		WithCancelAfter(CancelAfterHour).
+		WithPostOnly().
+		WithSelfTradePrevention(SelfTradeCancelOldest).
+		WithClientOID("d50ec984-77a8-460a-b958-66f114b0de9b")
+	data, err := json.Marshal(order)
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	expected := `{"client_oid":"d50ec984-77a8-460a-b958-66f114b0de9b","type":"limit","side":"buy","product_id":"BTC-USD","stp":"co","price":"100.00","size":"0.01","time_in_force":"GTT","cancel_after":"hour","post_only":true}`
+	if string(data) != expected {
+		t.Fatalf("Expected %v, actual = %v", expected, string(data))
+	}
+
+	data, err = json.Marshal(NewMarketOrderFunds(OrderSideSell, "ETH-USD", MustDecimal("25")))
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	expected = `{"type":"market","side":"sell","product_id":"ETH-USD","funds":"25"}`
+	if string(data) != expected {
+		t.Fatalf("Expected %v, actual = %v", expected, string(data))
+	}
+}
+
+func Test_OrderRequest_Validate(t *testing.T) {
+	price, size := MustDecimal("100.00"), MustDecimal("0.01")
+	valid := []*OrderRequest{
+		NewLimitOrder(OrderSideBuy, "BTC-USD", price, size),
+		NewLimitOrder(OrderSideBuy, "BTC-USD", price, size).WithTimeInForce(TimeInForceIOC),
+		NewLimitOrder(OrderSideSell, "BTC-USD", price, size).WithCancelAfter(CancelAfterDay).WithPostOnly(),
+		NewMarketOrder(OrderSideSell, "BTC-USD", size),
+		NewMarketOrderFunds(OrderSideBuy, "BTC-USD", price),
+		NewStopOrder(OrderSideSell, "BTC-USD", price, size),
+		NewStopOrderFunds(OrderSideBuy, "BTC-USD", price, price).WithSelfTradePrevention(SelfTradeCancelBoth),
+	}
+	for _, order := range valid {
+		if err := order.Validate(); err != nil {
+			t.Fatalf("Expected %+v to be valid, actual = %v", order, err)
+		}
+	}
+
+	market_with_both := NewMarketOrder(OrderSideBuy, "BTC-USD", size)
+	market_with_both.Funds = &price
+	market_with_price := NewMarketOrder(OrderSideBuy, "BTC-USD", size)
+	market_with_price.Price = &price
+	stop_without_price := NewStopOrder(OrderSideBuy, "BTC-USD", price, size)
+	stop_without_price.Price = nil
+	invalid := []struct {
+		order *OrderRequest
+		field string
+	}{
+		{NewLimitOrder("hold", "BTC-USD", price, size), "side"},
+		{NewLimitOrder(OrderSideBuy, "", price, size), "product_id"},
+		{NewLimitOrder(OrderSideBuy, "BTC-USD", price, size).WithClientOID("not-a-uuid"), "client_oid"},
+		{NewLimitOrder(OrderSideBuy, "BTC-USD", price, size).WithSelfTradePrevention("xx"), "stp"},
+		{NewLimitOrder(OrderSideBuy, "BTC-USD", MustDecimal("0"), size), "price"},
+		{NewLimitOrder(OrderSideBuy, "BTC-USD", price, MustDecimal("-1")), "size"},
+		{NewLimitOrder(OrderSideBuy, "BTC-USD", price, size).WithTimeInForce(TimeInForceGTT), "cancel_after"},
+		{&OrderRequest{Type: OrderTypeLimit, Side: OrderSideBuy, ProductID: "BTC-USD", Price: &price, Size: &size, CancelAfter: CancelAfterDay}, "cancel_after"},
+		{NewLimitOrder(OrderSideBuy, "BTC-USD", price, size).WithTimeInForce(TimeInForceFOK).WithPostOnly(), "post_only"},
+		{NewLimitOrder(OrderSideBuy, "BTC-USD", price, size).WithTimeInForce("DAY"), "time_in_force"},
+		{market_with_both, "size"},
+		{market_with_price, "price"},
+		{NewMarketOrder(OrderSideBuy, "BTC-USD", size).WithPostOnly(), "post_only"},
+		{NewMarketOrder(OrderSideBuy, "BTC-USD", size).WithTimeInForce(TimeInForceIOC), "time_in_force"},
+		{stop_without_price, "price"},
+		{&OrderRequest{Type: "iceberg", Side: OrderSideBuy, ProductID: "BTC-USD"}, "type"},
+	}
+	for _, test := range invalid {
+		err := test.order.Validate()
+		invalid_order, ok := err.(InvalidOrderError)
+		if !ok {
+			t.Fatalf("Expected an InvalidOrderError for %+v, actual = %v", test.order, err)
+		}
+		if invalid_order.Field != test.field {
+			t.Fatalf("Expected an error on %v, actual = %v", test.field, err)
+		}
+	}
+}
+
+func Test_PlaceOrder(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	var body map[string]interface{}
+	httpmock.RegisterResponder(
+		"POST",
+		"https://mock-api.gdax.com/orders",
+		func(req *http.Request) (*http.Response, error) {
+			data, _ := ioutil.ReadAll(req.Body)
+			json.Unmarshal(data, &body)
+			return httpmock.NewStringResponse(200, `
+				{
+					"id": "d0c5340b-6d6c-49d9-b567-48c4bfca13d2",
+					"price": "0.10000000",
+					"size": "0.01000000",
+					"product_id": "BTC-USD",
+					"side": "buy",
+					"stp": "dc",
+					"type": "limit",
+					"time_in_force": "GTC",
+					"post_only": false,
+					"created_at": "2016-12-08T20:02:28.53864Z",
+					"fill_fees": "0.0000000000000000",
+					"filled_size": "0.00000000",
+					"executed_value": "0.0000000000000000",
+					"status": "pending",
+					"settled": false
+				}
+			`), nil
+		},
+	)
+	client := NewMockClient()
+	output, err := PlaceOrder(client, NewLimitOrder(OrderSideBuy, "BTC-USD", MustDecimal("0.10000000"), MustDecimal("0.01000000")))
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if body["price"] != "0.10000000" || body["size"] != "0.01000000" || body["side"] != "buy" || body["type"] != "limit" {
+		t.Fatalf("Expected the order to be sent, actual body = %v", body)
+	}
+	if output.ID != "d0c5340b-6d6c-49d9-b567-48c4bfca13d2" {
+		t.Fatalf("Expected output.ID = d0c5340b-6d6c-49d9-b567-48c4bfca13d2, actual = %v", output.ID)
+	}
+	if output.Status != OrderStatusPending || output.Settled {
+		t.Fatalf("Expected a pending unsettled order, actual = %v", output)
+	}
+	if !output.Price.Equal(MustDecimal("0.1")) || output.TimeInForce != TimeInForceGTC || output.STP != SelfTradeDecrementAndCancel {
+		t.Fatalf("Expected a GTC order at 0.1, actual = %v", output)
+	}
+
+	// Invalid orders are not sent
+	body = nil
+	_, err = PlaceOrder(client, NewMarketOrder(OrderSideBuy, "BTC-USD", MustDecimal("0")))
+	if _, ok := err.(InvalidOrderError); !ok {
+		t.Fatalf("Expected an InvalidOrderError, actual = %v", err)
+	}
+	if body != nil {
+		t.Fatalf("Expected no request, actual body = %v", body)
+	}
+}
+
+func Test_PlaceOrder_InsufficientFunds(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"POST",
+		"https://mock-api.gdax.com/orders",
+		httpmock.NewStringResponder(400, `{ "message": "Insufficient funds" }`),
+	)
+	client := NewMockClient()
+	output, err := PlaceOrder(client, NewMarketOrderFunds(OrderSideBuy, "BTC-USD", MustDecimal("1000000")))
+	if !IsInsufficientFunds(err) {
+		t.Fatalf("Expected an insufficient funds error, actual = %v", err)
+	}
+	if output != nil {
+		t.Fatalf("Expected output to be nil, actual = %v", output)
+	}
+}
+
+//
+//
+//
+
+func Test_CancelOrder(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"DELETE",
+		"https://mock-api.gdax.com/orders/d0c5340b-6d6c-49d9-b567-48c4bfca13d2",
+		httpmock.NewStringResponder(200, `["d0c5340b-6d6c-49d9-b567-48c4bfca13d2"]`),
+	)
+	httpmock.RegisterResponder(
+		"DELETE",
+		"https://mock-api.gdax.com/orders/144c6f8e-713f-4682-8435-5280fbe8b2b4",
+		httpmock.NewStringResponder(200, ``),
+	)
+	httpmock.RegisterResponder(
+		"DELETE",
+		"https://mock-api.gdax.com/orders/debe4907-95dc-442f-af3b-cec12f42ebda",
+		httpmock.NewStringResponder(400, `{ "message": "Order already done" }`),
+	)
+	httpmock.RegisterResponder(
+		"DELETE",
+		"https://mock-api.gdax.com/orders/cf7aceee-7b08-4227-a76c-3858144323ab",
+		httpmock.NewStringResponder(404, `{ "message": "NotFound" }`),
+	)
+	client := NewMockClient()
+	order_id, err := CancelOrder(client, "d0c5340b-6d6c-49d9-b567-48c4bfca13d2")
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if order_id != "d0c5340b-6d6c-49d9-b567-48c4bfca13d2" {
+		t.Fatalf("Expected order_id = d0c5340b-6d6c-49d9-b567-48c4bfca13d2, actual = %v", order_id)
+	}
+	order_id, err = CancelOrder(client, "144c6f8e-713f-4682-8435-5280fbe8b2b4")
+	if err != nil {
+		t.Fatalf("Error should be nil for an empty body, %v", err)
+	}
+	if order_id != "144c6f8e-713f-4682-8435-5280fbe8b2b4" {
+		t.Fatalf("Expected order_id = 144c6f8e-713f-4682-8435-5280fbe8b2b4, actual = %v", order_id)
+	}
+	_, err = CancelOrder(client, "debe4907-95dc-442f-af3b-cec12f42ebda")
+	if !IsOrderAlreadyDone(err) || IsOrderNotFound(err) {
+		t.Fatalf("Expected an order already done error, actual = %v", err)
+	}
+	_, err = CancelOrder(client, "cf7aceee-7b08-4227-a76c-3858144323ab")
+	if !IsOrderNotFound(err) || IsOrderAlreadyDone(err) {
+		t.Fatalf("Expected an order not found error, actual = %v", err)
+	}
+}
+
+func Test_CancelAllOrders(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	product_ids := []string{}
+	httpmock.RegisterResponder(
+		"DELETE",
+		"https://mock-api.gdax.com/orders",
+		func(req *http.Request) (*http.Response, error) {
+			product_id := req.URL.Query().Get("product_id")
+			product_ids = append(product_ids, product_id)
+			if product_id == "BTC-USD" {
+				return httpmock.NewStringResponse(200, `["144c6f8e-713f-4682-8435-5280fbe8b2b4"]`), nil
+			}
+			if product_id == "XXX-USD" {
+				return httpmock.NewStringResponse(400, `{ "message": "Invalid product_id" }`), nil
+			}
+			return httpmock.NewStringResponse(200, `
+				[
+					"144c6f8e-713f-4682-8435-5280fbe8b2b4",
+					"debe4907-95dc-442f-af3b-cec12f42ebda"
+				]
+			`), nil
+		},
+	)
+	client := NewMockClient()
+	canceled, err := CancelAllOrders(client)
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if len(canceled) != 2 || canceled[1] != "debe4907-95dc-442f-af3b-cec12f42ebda" {
+		t.Fatalf("Expected 2 canceled orders, actual = %v", canceled)
+	}
+	canceled, err = CancelProductOrders(client, "BTC-USD")
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if len(canceled) != 1 || canceled[0] != "144c6f8e-713f-4682-8435-5280fbe8b2b4" {
+		t.Fatalf("Expected 1 canceled order, actual = %v", canceled)
+	}
+	if len(product_ids) != 2 || product_ids[0] != "" || product_ids[1] != "BTC-USD" {
+		t.Fatalf("Expected product_id to only be sent for the product, actual = %v", product_ids)
+	}
+	// The query string is not part of the path of the error
+	_, err = CancelProductOrders(client, "XXX-USD")
+	client_error, ok := AsClientError(err)
+	if !ok || client_error.Path != "/orders" {
+		t.Fatalf("Expected a ClientError for /orders, actual = %v", err)
+	}
+}
+
+//
+//
+//
+
+func Test_GetOrders(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	var query url.Values
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/orders",
+		func(req *http.Request) (*http.Response, error) {
+			query = req.URL.Query()
+			res := httpmock.NewStringResponse(200, `
+				[
+					{
+						"id": "d50ec984-77a8-460a-b958-66f114b0de9b",
+						"size": "3.0",
+						"price": "100.23",
+						"product_id": "BTC-USD",
+						"status": "open",
+						"filled_size": "1.23",
+						"fill_fees": "0.001",
+						"settled": false,
+						"side": "buy",
+						"created_at": "2014-11-14T06:39:55.189376Z"
+					}
+				]
+			`)
+			res.Header.Set("CB-AFTER", "d50ec984-77a8-460a-b958-66f114b0de9b")
+			return res, nil
+		},
+	)
+	client := NewMockClient()
+	filter := OrderFilter{Statuses: []OrderStatus{OrderStatusDone, OrderStatusPending}, ProductID: "BTC-USD"}
+	output, cursor, err := GetOrdersPage(client, filter, Pagination{Limit: 10})
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	statuses := query["status"]
+	if len(statuses) != 2 || statuses[0] != "done" || statuses[1] != "pending" {
+		t.Fatalf("Expected status = [done pending], actual = %v", statuses)
+	}
+	if query.Get("product_id") != "BTC-USD" || query.Get("limit") != "10" {
+		t.Fatalf("Expected product_id = BTC-USD and limit = 10, actual = %v", query)
+	}
+	if cursor.After != "d50ec984-77a8-460a-b958-66f114b0de9b" {
+		t.Fatalf("Expected cursor.After = d50ec984-77a8-460a-b958-66f114b0de9b, actual = %v", cursor.After)
+	}
+	if len(output) != 1 {
+		t.Fatalf("Expected output.length = 1, actual = %v", len(output))
+	}
+	order := output[0]
+	if order.Status != OrderStatusOpen || !order.FilledSize.Equal(MustDecimal("1.23")) || !order.FillFees.Equal(MustDecimal("0.001")) {
+		t.Fatalf("Expected an open order with 1.23 filled, actual = %v", order)
+	}
+
+	_, err = GetOrders(client, OrderFilter{Statuses: []OrderStatus{OrderStatusAll}})
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if query.Get("status") != "all" || query.Get("product_id") != "" {
+		t.Fatalf("Expected status = all without product_id, actual = %v", query)
+	}
+}
+
+func Test_GetOrder(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	order := `
+		{
+			"id": "68e6a28f-ae28-4788-8d4f-5ab4e5e5ae08",
+			"client_oid": "0a205de4-dd35-4370-a285-fe8fc375a273",
+			"size": "1.00000000",
+			"product_id": "BTC-USD",
+			"side": "buy",
+			"stp": "dc",
+			"funds": "9.9750623400000000",
+			"specified_funds": "10.0000000000000000",
+			"type": "market",
+			"post_only": false,
+			"created_at": "2016-12-08T20:09:05.508883Z",
+			"done_at": "2016-12-08T20:09:05.527Z",
+			"done_reason": "filled",
+			"fill_fees": "0.0249376391550000",
+			"filled_size": "0.01291771",
+			"executed_value": "9.9750556620000000",
+			"status": "done",
+			"settled": true
+		}
+	`
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/orders/68e6a28f-ae28-4788-8d4f-5ab4e5e5ae08",
+		httpmock.NewStringResponder(200, order),
+	)
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/orders/client:0a205de4-dd35-4370-a285-fe8fc375a273",
+		httpmock.NewStringResponder(200, order),
+	)
+	client := NewMockClient()
+	output, err := GetOrder(client, "68e6a28f-ae28-4788-8d4f-5ab4e5e5ae08")
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if output.Status != OrderStatusDone || !output.Settled || output.DoneReason != "filled" {
+		t.Fatalf("Expected a settled filled order, actual = %v", output)
+	}
+	if output.ExecutedValue.String() != "9.9750556620000000" || output.SpecifiedFunds.String() != "10.0000000000000000" {
+		t.Fatalf("Expected executed_value 9.9750556620000000 of 10 funds, actual = %v", output)
+	}
+	if !output.DoneAt.Equal(time.Date(2016, 12, 8, 20, 9, 5, 527000000, time.UTC)) {
+		t.Fatalf("Expected done_at = 2016-12-08T20:09:05.527Z, actual = %v", output.DoneAt)
+	}
+
+	output, err = GetOrderByClientOID(client, "0a205de4-dd35-4370-a285-fe8fc375a273")
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if output.ID != "68e6a28f-ae28-4788-8d4f-5ab4e5e5ae08" || output.ClientOID != "0a205de4-dd35-4370-a285-fe8fc375a273" {
+		t.Fatalf("Expected the order placed with the client_oid, actual = %v", output)
+	}
+}
@@ -0,0 +1,235 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+/*
+	A profile (or portfolio) of the user. Every profile has its own accounts, orders and fills.
+*/
+type Profile struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Name      string    `json:"name"`
+	Active    bool      `json:"active"`
+	IsDefault bool      `json:"is_default"`
+	CreatedAt time.Time `json:"created_at"`
+}
+type ProfilesResponse []Profile
+
+/*
+	List Profiles
+
+	HTTP REQUEST
+		GET /profiles
+
+	QUERY PARAMETERS
+	| Param | Description |
+	| active | [optional] Only return active profiles if set true |
+
+	HTTP RESPONSE
+	[
+		{
+			"id": "86602c68-306a-4500-ac73-4ce56a91d83c",
+			"user_id": "5844eceecf7e803e259d0365",
+			"name": "default",
+			"active": true,
+			"is_default": true,
+			"created_at": "2019-11-18T15:08:40.236309Z"
+		}
+	]
+*/
+func GetProfiles(client *Client, active_only bool) (ProfilesResponse, error) {
+	return GetProfilesContext(context.Background(), client, active_only)
+}
+
+func GetProfilesContext(ctx context.Context, client *Client, active_only bool) (ProfilesResponse, error) {
+	params := url.Values{}
+	if active_only {
+		params.Set("active", "true")
+	}
+	output := ProfilesResponse{}
+	_, err := client.GetContext(ctx, "/profiles", params, &output)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+/*
+	Get a Profile
+
+	HTTP REQUEST
+		GET /profiles/:profile_id
+
+	HTTP RESPONSE
+	{
+		"id": "86602c68-306a-4500-ac73-4ce56a91d83c",
+		"user_id": "5844eceecf7e803e259d0365",
+		"name": "default",
+		"active": true,
+		"is_default": true,
+		"created_at": "2019-11-18T15:08:40.236309Z"
+	}
+*/
+func GetProfile(client *Client, profile_id string) (*Profile, error) {
+	return GetProfileContext(context.Background(), client, profile_id)
+}
+
+func GetProfileContext(ctx context.Context, client *Client, profile_id string) (*Profile, error) {
+	output := &Profile{}
+	_, err := client.GetContext(ctx, fmt.Sprintf("/profiles/%s", profile_id), url.Values{}, output)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+type profileTransferRequest struct {
+	From     string  `json:"from"`
+	To       string  `json:"to"`
+	Currency string  `json:"currency"`
+	Amount   Decimal `json:"amount"`
+}
+
+/*
+	Create profile transfer
+	Transfer an amount of currency from one profile to another.
+
+	HTTP REQUEST
+		POST /profiles/transfer
+
+	PARAMETERS
+	| Param | Description |
+	| from | The profile id the API key belongs to and where the funds are sourced |
+	| to | The target profile id of where funds will be transferred to |
+	| currency | i.e. BTC or USD |
+	| amount | Amount of currency to be transferred |
+
+	The response has no body of interest, a nil error means the funds were moved.
+*/
+func TransferBetweenProfiles(client *Client, from, to, currency string, amount Decimal) error {
+	return TransferBetweenProfilesContext(context.Background(), client, from, to, currency, amount)
+}
+
+func TransferBetweenProfilesContext(ctx context.Context, client *Client, from, to, currency string, amount Decimal) error {
+	if from == "" || to == "" || from == to {
+		return fmt.Errorf("clients: invalid profile transfer from %q to %q", from, to)
+	}
+	if currency == "" {
+		return fmt.Errorf("clients: invalid profile transfer, currency is required")
+	}
+	if amount.Sign() <= 0 {
+		return fmt.Errorf("clients: invalid profile transfer amount %v, must be greater than 0", amount)
+	}
+	request := profileTransferRequest{From: from, To: to, Currency: currency, Amount: amount}
+	output := json.RawMessage{}
+	_, err := client.PostContext(ctx, "/profiles/transfer", request, &output)
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+/*
+	Copy of the client whose private requests act on the profile profile_id. The copy shares the
+	HTTP client, rate limiter, cache and credentials of c.
+
+	Orders, fills, deposits, withdrawals and new reports are sent with the profile_id. The accounts
+	of a profile, their ledger and holds are only available to an API key of that profile: these
+	requests fail with a ProfileNotSupportedError instead of returning the accounts of the default
+	profile. The endpoints of the user (trailing volume, payment methods, Coinbase accounts, ...)
+	and the lookups by id do not depend on the profile and are sent unchanged.
+
+		trading := client.ForProfile("86602c68-306a-4500-ac73-4ce56a91d83c")
+		order, err := PlaceOrder(trading, NewLimitOrder(OrderSideBuy, "BTC-USD", price, size))
+*/
+func (c *Client) ForProfile(profile_id string) *Client {
+	scoped := *c
+	scoped.Middleware = append([]Middleware{}, c.Middleware...)
+	scoped.ProfileID = profile_id
+	return &scoped
+}
+
+/*
+	Returned for a request of a profile scoped client to an endpoint that cannot act on another
+	profile than the one of the API key
+*/
+type ProfileNotSupportedError struct {
+	Pathname  string
+	ProfileID string
+}
+
+func (e ProfileNotSupportedError) Error() string {
+	return fmt.Sprintf("clients: %s cannot be scoped to profile %s, use an API key of that profile", e.Pathname, e.ProfileID)
+}
+
+/*
+	Endpoints accepting a profile_id
+*/
+var profileScopedPathnames = map[string]bool{
+	"/orders":                       true,
+	"/fills":                        true,
+	"/reports":                      true,
+	"/deposits/payment-method":      true,
+	"/deposits/coinbase-account":    true,
+	"/withdrawals/payment-method":   true,
+	"/withdrawals/coinbase-account": true,
+	"/withdrawals/crypto":           true,
+}
+
+/*
+	Endpoints answering for the profile of the API key only
+*/
+func isProfileBoundPathname(pathname string) bool {
+	return pathname == "/accounts" || strings.HasPrefix(pathname, "/accounts/")
+}
+
+/*
+	Add the profile_id of the client to a request of a profile scoped endpoint: to the query string
+	of requests without a body and to the JSON object of requests with one. Requests that already
+	name a profile are left untouched, requests that cannot be scoped fail.
+*/
+func (c *Client) applyProfile(pathname string, url_params url.Values, encoded_data []byte) (url.Values, []byte, error) {
+	if c.ProfileID == "" {
+		return url_params, encoded_data, nil
+	}
+	if isProfileBoundPathname(pathname) {
+		return nil, nil, ProfileNotSupportedError{Pathname: pathname, ProfileID: c.ProfileID}
+	}
+	if !profileScopedPathnames[pathname] {
+		return url_params, encoded_data, nil
+	}
+	if len(encoded_data) == 0 {
+		if url_params.Get("profile_id") != "" {
+			return url_params, encoded_data, nil
+		}
+		params := url.Values{}
+		for key, values := range url_params {
+			params[key] = values
+		}
+		params.Set("profile_id", c.ProfileID)
+		return params, encoded_data, nil
+	}
+	body := map[string]json.RawMessage{}
+	if err := json.Unmarshal(encoded_data, &body); err != nil {
+		// Not a JSON object, there is nowhere to put the profile
+		return url_params, encoded_data, nil
+	}
+	if _, ok := body["profile_id"]; ok {
+		return url_params, encoded_data, nil
+	}
+	profile_id, err := json.Marshal(c.ProfileID)
+	if err != nil {
+		return nil, nil, err
+	}
+	body["profile_id"] = profile_id
+	encoded_data, err = json.Marshal(body)
+	return url_params, encoded_data, err
+}
@@ -0,0 +1,241 @@
+package clients
+
+import (
+	"encoding/json"
+	"gopkg.in/jarcoal/httpmock.v1"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const mockProfile = `
+	{
+		"id": "86602c68-306a-4500-ac73-4ce56a91d83c",
+		"user_id": "5844eceecf7e803e259d0365",
+		"name": "trading",
+		"active": true,
+		"is_default": false,
+		"created_at": "2019-11-18T15:08:40.236309Z"
+	}
+`
+
+func Test_GetProfiles(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	var active string
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/profiles",
+		func(req *http.Request) (*http.Response, error) {
+			active = req.URL.Query().Get("active")
+			return httpmock.NewStringResponse(200, "["+mockProfile+"]"), nil
+		},
+	)
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/profiles/86602c68-306a-4500-ac73-4ce56a91d83c",
+		httpmock.NewStringResponder(200, mockProfile),
+	)
+	client := NewMockClient()
+	profiles, err := GetProfiles(client, true)
+	if nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if active != "true" {
+		t.Fatalf("Expected active = true, actual = %v", active)
+	}
+	if len(profiles) != 1 {
+		t.Fatalf("Expected 1 profile, actual = %v", len(profiles))
+	}
+	expected := Profile{
+		ID:        "86602c68-306a-4500-ac73-4ce56a91d83c",
+		UserID:    "5844eceecf7e803e259d0365",
+		Name:      "trading",
+		Active:    true,
+		IsDefault: false,
+		CreatedAt: time.Date(2019, 11, 18, 15, 8, 40, 236309000, time.UTC),
+	}
+	if profiles[0] != expected {
+		t.Fatalf("Expected profile = %v, actual = %v", expected, profiles[0])
+	}
+	profile, err := GetProfile(client, "86602c68-306a-4500-ac73-4ce56a91d83c")
+	if nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if *profile != expected {
+		t.Fatalf("Expected profile = %v, actual = %v", expected, *profile)
+	}
+}
+
+func Test_TransferBetweenProfiles(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	body := map[string]string{}
+	httpmock.RegisterResponder(
+		"POST",
+		"https://mock-api.gdax.com/profiles/transfer",
+		func(req *http.Request) (*http.Response, error) {
+			data, _ := ioutil.ReadAll(req.Body)
+			if err := json.Unmarshal(data, &body); err != nil {
+				return nil, err
+			}
+			return httpmock.NewStringResponse(200, ""), nil
+		},
+	)
+	client := NewMockClient().ForProfile("86602c68-306a-4500-ac73-4ce56a91d83c")
+	err := TransferBetweenProfiles(client, "86602c68-306a-4500-ac73-4ce56a91d83c", "e87429d3-f0a7-4f28-8dff-8dd93d383de1", "BTC", MustDecimal("0.5"))
+	if nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	expected := map[string]string{
+		"from":     "86602c68-306a-4500-ac73-4ce56a91d83c",
+		"to":       "e87429d3-f0a7-4f28-8dff-8dd93d383de1",
+		"currency": "BTC",
+		"amount":   "0.5",
+	}
+	if len(body) != len(expected) {
+		t.Fatalf("Expected body = %v, actual = %v", expected, body)
+	}
+	for key, value := range expected {
+		if body[key] != value {
+			t.Fatalf("Expected body[%s] = %v, actual = %v", key, value, body[key])
+		}
+	}
+	for _, invalid := range []struct {
+		from, to, currency string
+		amount             Decimal
+	}{
+		{"", "e87429d3-f0a7-4f28-8dff-8dd93d383de1", "BTC", MustDecimal("1")},
+		{"86602c68-306a-4500-ac73-4ce56a91d83c", "86602c68-306a-4500-ac73-4ce56a91d83c", "BTC", MustDecimal("1")},
+		{"86602c68-306a-4500-ac73-4ce56a91d83c", "e87429d3-f0a7-4f28-8dff-8dd93d383de1", "", MustDecimal("1")},
+		{"86602c68-306a-4500-ac73-4ce56a91d83c", "e87429d3-f0a7-4f28-8dff-8dd93d383de1", "BTC", MustDecimal("0")},
+	} {
+		if err := TransferBetweenProfiles(client, invalid.from, invalid.to, invalid.currency, invalid.amount); err == nil {
+			t.Fatalf("Expected an error for the transfer %v", invalid)
+		}
+	}
+}
+
+func Test_ForProfile(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	queries := map[string]string{}
+	bodies := map[string]map[string]interface{}{}
+	record := func(name, body string) httpmock.Responder {
+		return func(req *http.Request) (*http.Response, error) {
+			queries[name] = req.URL.RawQuery
+			if req.Body != nil {
+				data, _ := ioutil.ReadAll(req.Body)
+				if len(data) > 0 {
+					decoded := map[string]interface{}{}
+					if err := json.Unmarshal(data, &decoded); err != nil {
+						return nil, err
+					}
+					bodies[name] = decoded
+				}
+			}
+			return httpmock.NewStringResponse(200, body), nil
+		}
+	}
+	httpmock.RegisterResponder("GET", "https://mock-api.gdax.com/fills", record("fills", "[]"))
+	httpmock.RegisterResponder("DELETE", "https://mock-api.gdax.com/orders", record("cancel", "[]"))
+	httpmock.RegisterResponder("POST", "https://mock-api.gdax.com/orders", record("order", `{ "id": "d0c5340b-6d6c-49d9-b567-48c4bfca13d2" }`))
+	httpmock.RegisterResponder("GET", "https://mock-api.gdax.com/products", record("products", "[]"))
+	httpmock.RegisterResponder("GET", "https://mock-api.gdax.com/accounts", record("accounts", "[]"))
+	httpmock.RegisterResponder("GET", "https://mock-api.gdax.com/users/self/trailing-volume", record("volume", "[]"))
+	httpmock.RegisterResponder("DELETE", "https://mock-api.gdax.com/orders/d0c5340b-6d6c-49d9-b567-48c4bfca13d2", record("cancel_order", `"d0c5340b-6d6c-49d9-b567-48c4bfca13d2"`))
+	httpmock.RegisterResponder("POST", "https://mock-api.gdax.com/reports", record("report", `{ "id": "0428b97b-bec1-429e-a94c-59232926778d", "type": "fills", "status": "pending" }`))
+	httpmock.RegisterResponder("POST", "https://mock-api.gdax.com/deposits/payment-method", record("deposit", `{ "id": "593533d2-ff31-46e0-b22e-ca754147a96a", "amount": "10.00", "currency": "USD" }`))
+	client := NewMockClient()
+	client.Cache = nil
+	scoped := client.ForProfile("86602c68-306a-4500-ac73-4ce56a91d83c")
+	if client.ProfileID != "" {
+		t.Fatalf("Expected the original client to stay unscoped, actual = %v", client.ProfileID)
+	}
+
+	// Orders and fills act on the profile
+	if _, err := GetFills(scoped, FillFilter{ProductID: "BTC-USD"}); nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if queries["fills"] != "product_id=BTC-USD&profile_id=86602c68-306a-4500-ac73-4ce56a91d83c" {
+		t.Fatalf("Expected the profile in the fills query, actual = %v", queries["fills"])
+	}
+	if _, err := GetFills(client, FillFilter{ProductID: "BTC-USD"}); nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if queries["fills"] != "product_id=BTC-USD" {
+		t.Fatalf("Expected no profile in the query of the original client, actual = %v", queries["fills"])
+	}
+	if _, err := CancelProductOrders(scoped, "BTC-USD"); nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if queries["cancel"] != "product_id=BTC-USD&profile_id=86602c68-306a-4500-ac73-4ce56a91d83c" {
+		t.Fatalf("Expected the profile in the cancel query, actual = %v", queries["cancel"])
+	}
+	if _, err := PlaceOrder(scoped, NewLimitOrder(OrderSideBuy, "BTC-USD", MustDecimal("100"), MustDecimal("0.01"))); nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if bodies["order"]["profile_id"] != "86602c68-306a-4500-ac73-4ce56a91d83c" || bodies["order"]["product_id"] != "BTC-USD" {
+		t.Fatalf("Expected the profile in the order body, actual = %v", bodies["order"])
+	}
+
+	if _, err := DepositFromPaymentMethod(scoped, MustDecimal("10.00"), "USD", "bc677162-d934-5f1a-968c-a496b1c1270b"); nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if bodies["deposit"]["profile_id"] != "86602c68-306a-4500-ac73-4ce56a91d83c" || bodies["deposit"]["currency"] != "USD" {
+		t.Fatalf("Expected the profile in the deposit body, actual = %v", bodies["deposit"])
+	}
+	start := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := CreateReport(scoped, NewFillsReport("BTC-USD", start, start.AddDate(0, 1, 0))); nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if bodies["report"]["profile_id"] != "86602c68-306a-4500-ac73-4ce56a91d83c" || bodies["report"]["type"] != "fills" {
+		t.Fatalf("Expected the profile in the report body, actual = %v", bodies["report"])
+	}
+
+	// The endpoints of the user and the lookups by id are sent without it
+	if _, err := GetProducts(scoped); nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if _, err := GetAccountTrailingVolume(scoped); nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if _, err := CancelOrder(scoped, "d0c5340b-6d6c-49d9-b567-48c4bfca13d2"); nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	for _, name := range []string{"products", "volume", "cancel_order"} {
+		if queries[name] != "" {
+			t.Fatalf("Expected no profile in the %s query, actual = %v", name, queries[name])
+		}
+	}
+
+	// The accounts of the profile cannot be listed with the key of another profile
+	requests := len(queries)
+	if _, err := GetAccounts(scoped); err != (ProfileNotSupportedError{Pathname: "/accounts", ProfileID: "86602c68-306a-4500-ac73-4ce56a91d83c"}) {
+		t.Fatalf("Expected a ProfileNotSupportedError, actual = %v", err)
+	}
+	if _, err := GetAccountLedger(scoped, "e316cb9a-0808-4fd7-8914-97829c1925de"); err == nil {
+		t.Fatalf("Expected the ledger to fail on a profile scoped client")
+	}
+	if _, err := GetAccountHolds(scoped, "e316cb9a-0808-4fd7-8914-97829c1925de"); err == nil {
+		t.Fatalf("Expected the holds to fail on a profile scoped client")
+	}
+	if len(queries) != requests {
+		t.Fatalf("Expected no request to be sent, actual = %v", queries)
+	}
+	if _, err := GetAccounts(client); nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+
+	with_profile, err := NewClient(WithBaseURL("https://mock-api.gdax.com"), WithProfile("86602c68-306a-4500-ac73-4ce56a91d83c"))
+	if nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if with_profile.ProfileID != "86602c68-306a-4500-ac73-4ce56a91d83c" {
+		t.Fatalf("Expected ProfileID to be set, actual = %v", with_profile.ProfileID)
+	}
+}
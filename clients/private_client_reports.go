@@ -0,0 +1,223 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	ReportTypeFills   = "fills"
+	ReportTypeAccount = "account"
+
+	ReportFormatPDF = "pdf"
+	ReportFormatCSV = "csv"
+
+	ReportStatusPending  = "pending"
+	ReportStatusCreating = "creating"
+	ReportStatusReady    = "ready"
+)
+
+/*
+	Body of POST /reports, built with NewFillsReport or NewAccountReport
+*/
+type ReportRequest struct {
+	Type      string    `json:"type"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	ProductID string    `json:"product_id,omitempty"`
+	AccountID string    `json:"account_id,omitempty"`
+	Format    string    `json:"format,omitempty"`
+	Email     string    `json:"email,omitempty"`
+}
+
+/*
+	Report of the fills of a product between start and end
+*/
+func NewFillsReport(product_id string, start, end time.Time) *ReportRequest {
+	return &ReportRequest{Type: ReportTypeFills, ProductID: product_id, StartDate: start, EndDate: end}
+}
+
+/*
+	Report of the ledger of an account between start and end
+*/
+func NewAccountReport(account_id string, start, end time.Time) *ReportRequest {
+	return &ReportRequest{Type: ReportTypeAccount, AccountID: account_id, StartDate: start, EndDate: end}
+}
+
+/*
+	Format of the file, ReportFormatPDF (the default) or ReportFormatCSV
+*/
+func (r *ReportRequest) WithFormat(format string) *ReportRequest {
+	r.Format = format
+	return r
+}
+
+/*
+	Email address to send the report to once it is ready
+*/
+func (r *ReportRequest) WithEmail(email string) *ReportRequest {
+	r.Email = email
+	return r
+}
+
+func (r *ReportRequest) Validate() error {
+	switch r.Type {
+	case ReportTypeFills:
+		if r.ProductID == "" {
+			return fmt.Errorf("clients: invalid report, product_id is required for a fills report")
+		}
+	case ReportTypeAccount:
+		if r.AccountID == "" {
+			return fmt.Errorf("clients: invalid report, account_id is required for an account report")
+		}
+	default:
+		return fmt.Errorf("clients: invalid report type %q, expected fills or account", r.Type)
+	}
+	if r.StartDate.IsZero() || r.EndDate.IsZero() || !r.StartDate.Before(r.EndDate) {
+		return fmt.Errorf("clients: invalid report dates, start_date must be before end_date")
+	}
+	switch r.Format {
+	case "", ReportFormatPDF, ReportFormatCSV:
+	default:
+		return fmt.Errorf("clients: invalid report format %q, expected pdf or csv", r.Format)
+	}
+	return nil
+}
+
+/*
+	Create a new report
+	Reports provide batches of historic information about your account in various human and
+	machine readable forms.
+
+	HTTP REQUEST
+		POST /reports
+
+	PARAMETERS
+	| Param | Description |
+	| type | fills or account |
+	| start_date | Starting date for the report (inclusive) |
+	| end_date | Ending date for the report (inclusive) |
+	| product_id | ID of the product to generate a fills report for. E.g. BTC-USD. Required if type is fills |
+	| account_id | ID of the account to generate an account report for. Required if type is account |
+	| format | pdf or csv (default is pdf) |
+	| email | Email address to send the report to (optional) |
+
+	HTTP RESPONSE
+	{
+		"id": "0428b97b-bec1-429e-a94c-59232926778d",
+		"type": "fills",
+		"status": "pending",
+		"created_at": "2015-01-06T10:34:47.000Z",
+		"completed_at": undefined,
+		"expires_at": "2015-01-13T10:35:47.000Z",
+		"file_url": undefined,
+		"params": {
+			"start_date": "2014-11-01T00:00:00.000Z",
+			"end_date": "2014-11-30T23:59:59.000Z"
+		}
+	}
+
+	The report will be generated when resources are available. Report status can be queried via
+	GetAccountReportStatus or awaited with WaitForReport.
+*/
+func CreateReport(client *Client, report *ReportRequest) (*AccountReportStatus, error) {
+	return CreateReportContext(context.Background(), client, report)
+}
+
+func CreateReportContext(ctx context.Context, client *Client, report *ReportRequest) (*AccountReportStatus, error) {
+	if err := report.Validate(); err != nil {
+		return nil, err
+	}
+	output := &AccountReportStatus{}
+	_, err := client.PostContext(ctx, "/reports", report, output)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+const (
+	// Delay between the first two status polls of WaitForReport when none is given
+	DefaultReportPollInterval = time.Second
+	// Upper bound of the delay between two status polls of WaitForReport
+	MaxReportPollInterval = 30 * time.Second
+)
+
+/*
+	Poll the status of a report until it is ready, waiting interval after the first poll and
+	doubling the delay after every poll up to MaxReportPollInterval.
+
+	Returns the ready report, or the error of a poll or of ctx, whichever comes first. Use a
+	context with a deadline to bound the wait.
+*/
+func WaitForReport(ctx context.Context, client *Client, report_id string, interval time.Duration) (*AccountReportStatus, error) {
+	if interval <= 0 {
+		interval = DefaultReportPollInterval
+	}
+	for {
+		report, err := GetAccountReportStatusContext(ctx, client, report_id)
+		if err != nil {
+			return nil, err
+		}
+		if report.Status == ReportStatusReady {
+			return report, nil
+		}
+		if err := sleepContext(ctx, interval); err != nil {
+			return nil, err
+		}
+		interval *= 2
+		if interval > MaxReportPollInterval {
+			interval = MaxReportPollInterval
+		}
+	}
+}
+
+/*
+	Stream the file of a ready report to w and return the number of bytes written.
+
+	The file is downloaded from FileURL through the HTTPClient of client, without the API
+	credentials since it is hosted outside of the API. The overall timeout of an *http.Client
+	does not apply, large files may take longer to download: bound the download with ctx.
+*/
+func DownloadReport(ctx context.Context, client *Client, report *AccountReportStatus, w io.Writer) (int64, error) {
+	if report.Status != ReportStatusReady || report.FileURL == "" {
+		return 0, fmt.Errorf("clients: report %s is not ready, status = %s", report.ID, report.Status)
+	}
+	// The query string of the file url holds its signature, it is kept out of every error
+	req, err := http.NewRequest("GET", report.FileURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("clients: invalid file url for report %s", report.ID)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Add("User-Agent", client.userAgent())
+	res, err := withoutTimeout(client.httpClient()).Do(req)
+	if err != nil {
+		if url_error, ok := err.(*url.Error); ok {
+			return 0, &url.Error{Op: url_error.Op, URL: req.URL.Scheme + "://" + req.URL.Host + req.URL.Path, Err: url_error.Err}
+		}
+		return 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != 200 {
+		body_data, _ := ioutil.ReadAll(res.Body)
+		return 0, newClientError("GET", req.URL.Path, res, body_data)
+	}
+	return io.Copy(w, res.Body)
+}
+
+/*
+	Copy of an *http.Client without its overall timeout, other doers are returned as is
+*/
+func withoutTimeout(doer Doer) Doer {
+	if http_client, ok := doer.(*http.Client); ok && http_client.Timeout > 0 {
+		copied := *http_client
+		copied.Timeout = 0
+		return &copied
+	}
+	return doer
+}
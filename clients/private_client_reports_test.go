@@ -0,0 +1,223 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"gopkg.in/jarcoal/httpmock.v1"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_CreateReport(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	var body map[string]string
+	httpmock.RegisterResponder(
+		"POST",
+		"https://mock-api.gdax.com/reports",
+		func(req *http.Request) (*http.Response, error) {
+			data, _ := ioutil.ReadAll(req.Body)
+			json.Unmarshal(data, &body)
+			return httpmock.NewStringResponse(200, `
+				{
+					"id": "0428b97b-bec1-429e-a94c-59232926778d",
+					"type": "fills",
+					"status": "pending",
+					"created_at": "2015-01-06T10:34:47.000Z",
+					"expires_at": "2015-01-13T10:35:47.000Z"
+				}
+			`), nil
+		},
+	)
+	client := NewMockClient()
+	start := time.Date(2014, 11, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2014, 11, 30, 23, 59, 59, 0, time.UTC)
+	report, err := CreateReport(client, NewFillsReport("BTC-USD", start, end).WithFormat(ReportFormatCSV).WithEmail("treasury@example.com"))
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if report.ID != "0428b97b-bec1-429e-a94c-59232926778d" || report.Status != ReportStatusPending {
+		t.Fatalf("Expected a pending report, actual = %v", report)
+	}
+	expected := map[string]string{
+		"type":       "fills",
+		"start_date": "2014-11-01T00:00:00Z",
+		"end_date":   "2014-11-30T23:59:59Z",
+		"product_id": "BTC-USD",
+		"format":     "csv",
+		"email":      "treasury@example.com",
+	}
+	if len(body) != len(expected) {
+		t.Fatalf("Expected body = %v, actual = %v", expected, body)
+	}
+	for key, value := range expected {
+		if body[key] != value {
+			t.Fatalf("Expected body = %v, actual = %v", expected, body)
+		}
+	}
+
+	invalid := []*ReportRequest{
+		NewFillsReport("", start, end),
+		NewAccountReport("", start, end),
+		NewAccountReport("e316cb9a-0808-4fd7-8914-97829c1925de", end, start),
+		NewAccountReport("e316cb9a-0808-4fd7-8914-97829c1925de", start, end).WithFormat("xls"),
+		&ReportRequest{Type: "orders", StartDate: start, EndDate: end},
+	}
+	for _, request := range invalid {
+		if _, err := CreateReport(client, request); err == nil {
+			t.Fatalf("Expected an error for %+v", request)
+		}
+	}
+}
+
+func Test_WaitForReport(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	polls := 0
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/reports/0428b97b-bec1-429e-a94c-59232926778d",
+		func(req *http.Request) (*http.Response, error) {
+			polls++
+			status, file_url := "creating", ""
+			if polls == 3 {
+				status, file_url = "ready", "https://mock-reports.gdax.com/0428b97b/fills.csv"
+			}
+			return httpmock.NewStringResponse(200, `
+				{
+					"id": "0428b97b-bec1-429e-a94c-59232926778d",
+					"type": "fills",
+					"status": "`+status+`",
+					"file_url": "`+file_url+`"
+				}
+			`), nil
+		},
+	)
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-reports.gdax.com/0428b97b/fills.csv",
+		httpmock.NewStringResponder(200, "portfolio,trade id,product\ndefault,74,BTC-USD\n"),
+	)
+	client := NewMockClient()
+	report, err := WaitForReport(context.Background(), client, "0428b97b-bec1-429e-a94c-59232926778d", time.Millisecond)
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if polls != 3 || report.Status != ReportStatusReady {
+		t.Fatalf("Expected the report to be ready after 3 polls, actual = %v after %v", report, polls)
+	}
+	buffer := &bytes.Buffer{}
+	written, err := DownloadReport(context.Background(), client, report, buffer)
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if buffer.String() != "portfolio,trade id,product\ndefault,74,BTC-USD\n" || written != int64(buffer.Len()) {
+		t.Fatalf("Expected the report file, actual = %q (%v bytes)", buffer.String(), written)
+	}
+}
+
+func Test_WaitForReport_Deadline(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/reports/0428b97b-bec1-429e-a94c-59232926778d",
+		httpmock.NewStringResponder(200, `{ "id": "0428b97b-bec1-429e-a94c-59232926778d", "status": "pending" }`),
+	)
+	client := NewMockClient()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := WaitForReport(ctx, client, "0428b97b-bec1-429e-a94c-59232926778d", time.Millisecond)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Expected context.DeadlineExceeded, actual = %v", err)
+	}
+}
+
+func Test_DownloadReport_Errors(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-reports.gdax.com/expired.csv",
+		httpmock.NewStringResponder(403, `<Error><Code>AccessDenied</Code></Error>`),
+	)
+	client := NewMockClient()
+	_, err := DownloadReport(context.Background(), client, &AccountReportStatus{ID: "pending", Status: ReportStatusPending}, &bytes.Buffer{})
+	if err == nil {
+		t.Fatalf("Expected an error for a pending report")
+	}
+	_, err = DownloadReport(context.Background(), client, &AccountReportStatus{Status: ReportStatusReady, FileURL: "https://mock-reports.gdax.com/expired.csv?Signature=secret"}, &bytes.Buffer{})
+	if !IsForbidden(err) {
+		t.Fatalf("Expected a forbidden error, actual = %v", err)
+	}
+	client_error, _ := AsClientError(err)
+	if client_error.Path != "/expired.csv" || strings.Contains(err.Error(), "secret") {
+		t.Fatalf("Expected the signature to be left out of the error, actual = %v", err)
+	}
+}
+
+type failingDoer struct{}
+
+func (failingDoer) Do(req *http.Request) (*http.Response, error) {
+	return nil, &url.Error{Op: "Get", URL: req.URL.String(), Err: errors.New("connection reset")}
+}
+
+func Test_DownloadReport_TransportError(t *testing.T) {
+	client := NewMockClient()
+	client.HTTPClient = failingDoer{}
+	report := &AccountReportStatus{Status: ReportStatusReady, FileURL: "https://s3.example.com/r.csv?X-Amz-Signature=SECRET"}
+	_, err := DownloadReport(context.Background(), client, report, &bytes.Buffer{})
+	if err == nil {
+		t.Fatalf("Expected the transport error")
+	}
+	if strings.Contains(err.Error(), "SECRET") || !strings.Contains(err.Error(), "https://s3.example.com/r.csv") {
+		t.Fatalf("Expected the signature to be left out of the error, actual = %v", err)
+	}
+	if url_error, ok := err.(*url.Error); !ok || url_error.Err.Error() != "connection reset" {
+		t.Fatalf("Expected the url error to be kept, actual = %#v", err)
+	}
+}
+
+func Test_DownloadReport_NoTimeout(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-reports.gdax.com/large.csv",
+		func(req *http.Request) (*http.Response, error) {
+			select {
+			case <-time.After(100 * time.Millisecond):
+				return httpmock.NewStringResponse(200, "portfolio,trade id"), nil
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			}
+		},
+	)
+	client := NewMockClient()
+	client.HTTPClient = &http.Client{Timeout: 20 * time.Millisecond}
+	report := &AccountReportStatus{Status: ReportStatusReady, FileURL: "https://mock-reports.gdax.com/large.csv"}
+	n, err := DownloadReport(context.Background(), client, report, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("Expected the download to outlast the client timeout, actual = %v", err)
+	}
+	if n != 18 {
+		t.Fatalf("Expected 18 bytes, actual = %v", n)
+	}
+	// The context still bounds the download
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := DownloadReport(ctx, client, report, &bytes.Buffer{}); err == nil {
+		t.Fatalf("Expected the download to be canceled with its context")
+	}
+}
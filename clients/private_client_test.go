@@ -1,7 +1,10 @@
 package clients
 
 import (
+	"context"
+	"encoding/json"
 	"gopkg.in/jarcoal/httpmock.v1"
+	"net/http"
 	"reflect"
 	"testing"
 	"time"
@@ -108,8 +111,8 @@ func Test_mock_GetAccountTrailingVolume(t *testing.T) {
 	client := NewMockClient()
 	expected := AccountTrailingVolume{
 		ProductID:      "BTC-USD",
-		ExchangeVolume: 11800.00000000,
-		Volume:         100.00000000,
+		ExchangeVolume: MustDecimal("11800.00000000"),
+		Volume:         MustDecimal("100.00000000"),
 		RecordedAt:     time.Date(1973, 11, 29, 00, 05, 01, 123456*1000, time.UTC),
 	}
 	output, err := GetAccountTrailingVolume(client)
@@ -125,10 +128,391 @@ func Test_mock_GetAccountTrailingVolume(t *testing.T) {
 	if output[0].ProductID != expected.ProductID {
 		t.Fatalf("Expected output.ProductID %v to match expected %v", output[0].ProductID, expected.ProductID)
 	}
-	if output[0].ExchangeVolume != expected.ExchangeVolume {
+	if !output[0].ExchangeVolume.Equal(expected.ExchangeVolume) {
 		t.Fatalf("Expected output.ExchangeVolume %v to match expected %v", output[0].ExchangeVolume, expected.ExchangeVolume)
 	}
-	if output[0].Volume != expected.Volume {
+	if !output[0].Volume.Equal(expected.Volume) {
 		t.Fatalf("Expected output.Volume %v to match expected %v", output[0].Volume, expected.Volume)
 	}
 }
+
+//
+//
+//
+
+func Test_GetAccounts(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/accounts",
+		httpmock.NewStringResponder(
+			200,
+			`
+				[
+					{
+						"id": "71452118-efc7-4cc4-8780-a5e22d4baa53",
+						"currency": "BTC",
+						"balance": "0.0000000000000000",
+						"available": "0.0000000000000000",
+						"hold": "0.0000000000000000",
+						"profile_id": "75da88c5-05bf-4f54-bc85-5c775bd68254"
+					},
+					{
+						"id": "e316cb9a-0808-4fd7-8914-97829c1925de",
+						"currency": "USD",
+						"balance": "80.2301373066930000",
+						"available": "79.2266348066930000",
+						"hold": "1.0035025000000000",
+						"profile_id": "75da88c5-05bf-4f54-bc85-5c775bd68254"
+					}
+				]
+			`,
+		),
+	)
+	client := NewMockClient()
+	output, err := GetAccounts(client)
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if len(output) != 2 {
+		t.Fatalf("Expected output.length = 2, actual = %v", len(output))
+	}
+	usd := output.Find("USD")
+	if usd == nil {
+		t.Fatalf("Expected a USD account, actual = %v", output)
+	}
+	if usd.ID != "e316cb9a-0808-4fd7-8914-97829c1925de" {
+		t.Fatalf("Expected usd.ID = e316cb9a-0808-4fd7-8914-97829c1925de, actual = %v", usd.ID)
+	}
+	if usd.Balance.String() != "80.2301373066930000" {
+		t.Fatalf("Expected usd.Balance = 80.2301373066930000, actual = %v", usd.Balance)
+	}
+	if !usd.Available.Add(usd.Hold).Equal(usd.Balance) {
+		t.Fatalf("Expected usd.Available + usd.Hold = usd.Balance, actual = %v + %v", usd.Available, usd.Hold)
+	}
+	if usd.ProfileID != "75da88c5-05bf-4f54-bc85-5c775bd68254" {
+		t.Fatalf("Expected usd.ProfileID = 75da88c5-05bf-4f54-bc85-5c775bd68254, actual = %v", usd.ProfileID)
+	}
+	if output.Find("ETH") != nil {
+		t.Fatalf("Expected no ETH account")
+	}
+}
+
+func Test_GetAccount(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/accounts/e316cb9a-0808-4fd7-8914-97829c1925de",
+		httpmock.NewStringResponder(
+			200,
+			`
+				{
+					"id": "e316cb9a-0808-4fd7-8914-97829c1925de",
+					"currency": "USD",
+					"balance": "1.100",
+					"available": "1.00",
+					"hold": "0.100",
+					"profile_id": "75da88c5-05bf-4f54-bc85-5c775bd68254"
+				}
+			`,
+		),
+	)
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/accounts/unknown",
+		httpmock.NewStringResponder(404, `{ "message": "NotFound" }`),
+	)
+	client := NewMockClient()
+	output, err := GetAccount(client, "e316cb9a-0808-4fd7-8914-97829c1925de")
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if output.Currency != "USD" {
+		t.Fatalf("Expected output.Currency = USD, actual = %v", output.Currency)
+	}
+	if !output.Balance.Equal(MustDecimal("1.1")) || !output.Available.Equal(MustDecimal("1")) || !output.Hold.Equal(MustDecimal("0.1")) {
+		t.Fatalf("Expected balance 1.1, available 1 and hold 0.1, actual = %v", output)
+	}
+
+	output, err = GetAccount(client, "unknown")
+	if !IsNotFound(err) {
+		t.Fatalf("Expected a not found error, actual = %v", err)
+	}
+	if output != nil {
+		t.Fatalf("Expected output to be nil, actual = %v", output)
+	}
+}
+
+//
+//
+//
+
+func Test_GetAccountLedger(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/accounts/e316cb9a-0808-4fd7-8914-97829c1925de/ledger",
+		httpmock.NewStringResponder(
+			200,
+			`
+				[
+					{
+						"id": "100",
+						"created_at": "2014-11-07T08:19:27.028459Z",
+						"amount": "0.001",
+						"balance": "239.669",
+						"type": "fee",
+						"details": {
+							"order_id": "d50ec984-77a8-460a-b958-66f114b0de9b",
+							"trade_id": 74,
+							"product_id": "BTC-USD"
+						}
+					},
+					{
+						"id": "99",
+						"created_at": "2014-11-07T08:10:00.000000Z",
+						"amount": "-100.00",
+						"balance": "239.670",
+						"type": "transfer",
+						"details": {
+							"transfer_id": "5d3a4c88-3e1c-4d54-a9d8-7ae2d4b3d2a1",
+							"transfer_type": "withdraw"
+						}
+					}
+				]
+			`,
+		),
+	)
+	client := NewMockClient()
+	output, err := GetAccountLedger(client, "e316cb9a-0808-4fd7-8914-97829c1925de")
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if len(output) != 2 {
+		t.Fatalf("Expected output.length = 2, actual = %v", len(output))
+	}
+	fee := output[0]
+	if fee.Type != LedgerEntryFee {
+		t.Fatalf("Expected fee.Type = fee, actual = %v", fee.Type)
+	}
+	if !fee.Amount.Equal(MustDecimal("0.001")) || !fee.Balance.Equal(MustDecimal("239.669")) {
+		t.Fatalf("Expected amount 0.001 and balance 239.669, actual = %v and %v", fee.Amount, fee.Balance)
+	}
+	expected := LedgerEntryDetails{
+		OrderID:   "d50ec984-77a8-460a-b958-66f114b0de9b",
+		TradeID:   "74",
+		ProductID: "BTC-USD",
+	}
+	if !reflect.DeepEqual(fee.Details, expected) {
+		t.Fatalf("Expected fee.Details = %v, actual = %v", expected, fee.Details)
+	}
+	transfer := output[1]
+	if transfer.Type != LedgerEntryTransfer || transfer.Details.TransferID != "5d3a4c88-3e1c-4d54-a9d8-7ae2d4b3d2a1" || transfer.Details.TransferType != "withdraw" {
+		t.Fatalf("Expected a withdraw transfer, actual = %v", transfer)
+	}
+}
+
+func Test_AccountLedgerIterator(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	pages := map[string]string{
+		"": `[
+			{ "id": "6", "created_at": "2017-01-06T00:00:00Z", "amount": "1", "balance": "6", "type": "match", "details": {} },
+			{ "id": "5", "created_at": "2017-01-05T00:00:00Z", "amount": "1", "balance": "5", "type": "match", "details": {} },
+			{ "id": "4", "created_at": "2017-01-04T00:00:00Z", "amount": "1", "balance": "4", "type": "rebate", "details": {} }
+		]`,
+		"4": `[
+			{ "id": "3", "created_at": "2017-01-03T00:00:00Z", "amount": "1", "balance": "3", "type": "match", "details": {} },
+			{ "id": "2", "created_at": "2017-01-02T00:00:00Z", "amount": "1", "balance": "2", "type": "match", "details": {} }
+		]`,
+		"2": `[
+			{ "id": "1", "created_at": "2017-01-01T00:00:00Z", "amount": "1", "balance": "1", "type": "match", "details": {} }
+		]`,
+	}
+	requests := []string{}
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/accounts/e316cb9a-0808-4fd7-8914-97829c1925de/ledger",
+		func(req *http.Request) (*http.Response, error) {
+			after := req.URL.Query().Get("after")
+			requests = append(requests, after)
+			res := httpmock.NewStringResponse(200, pages[after])
+			switch after {
+			case "":
+				res.Header.Set("CB-AFTER", "4")
+			case "4":
+				res.Header.Set("CB-AFTER", "2")
+			}
+			return res, nil
+		},
+	)
+	client := NewMockClient()
+	start := time.Date(2017, 1, 3, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2017, 1, 6, 0, 0, 0, 0, time.UTC)
+	iterator := NewAccountLedgerIterator(client, "e316cb9a-0808-4fd7-8914-97829c1925de", start, end)
+	ids := []string{}
+	err := iterator.ForEach(context.Background(), func(entry LedgerEntry) error {
+		ids = append(ids, entry.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if !reflect.DeepEqual(ids, []string{"5", "4", "3"}) {
+		t.Fatalf("Expected ids = [5 4 3], actual = %v", ids)
+	}
+	if !reflect.DeepEqual(requests, []string{"", "4"}) {
+		t.Fatalf("Expected to stop before the last page, actual requests = %v", requests)
+	}
+}
+
+func Test_AccountLedgerIterator_details(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/accounts/e316cb9a-0808-4fd7-8914-97829c1925de/ledger",
+		func(req *http.Request) (*http.Response, error) {
+			if req.URL.Query().Get("after") == "" {
+				res := httpmock.NewStringResponse(200, `[
+					{ "id": "2", "created_at": "2017-01-02T00:00:00Z", "amount": "1", "balance": "2", "type": "match",
+						"details": { "order_id": "d50ec984-77a8-460a-b958-66f114b0de9b", "trade_id": "74", "product_id": "BTC-USD" } }
+				]`)
+				res.Header.Set("CB-AFTER", "2")
+				return res, nil
+			}
+			return httpmock.NewStringResponse(200, `[
+				{ "id": "1", "created_at": "2017-01-01T00:00:00Z", "amount": "1", "balance": "1", "type": "transfer",
+					"details": { "transfer_id": "5d3a4c88-3e1c-4d54-a9d8-7ae2d4b3d2a1", "transfer_type": "deposit" } }
+			]`), nil
+		},
+	)
+	client := NewMockClient()
+	iterator := NewAccountLedgerIterator(client, "e316cb9a-0808-4fd7-8914-97829c1925de", time.Time{}, time.Time{})
+	entries := []LedgerEntry{}
+	err := iterator.ForEach(context.Background(), func(entry LedgerEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, actual = %v", entries)
+	}
+	expected := LedgerEntryDetails{TransferID: "5d3a4c88-3e1c-4d54-a9d8-7ae2d4b3d2a1", TransferType: "deposit"}
+	if entries[1].Details != expected {
+		t.Fatalf("Expected the transfer details = %v, actual = %v", expected, entries[1].Details)
+	}
+	if entries[0].Details.TradeID != "74" {
+		t.Fatalf("Expected the match details to be kept, actual = %v", entries[0].Details)
+	}
+	// Decoding into used details resets them
+	details := entries[0].Details
+	if err := json.Unmarshal([]byte(`{ "transfer_id": "5d3a4c88-3e1c-4d54-a9d8-7ae2d4b3d2a1", "transfer_type": "deposit" }`), &details); err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if details != expected {
+		t.Fatalf("Expected the transfer details = %v, actual = %v", expected, details)
+	}
+}
+
+//
+//
+//
+
+func Test_GetAccountHolds(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/accounts/e0b3f39a-183d-453e-b754-0c13e5bab0b3/holds",
+		httpmock.NewStringResponder(
+			200,
+			`
+				[
+					{
+						"id": "82dcd140-c3c7-4507-8de4-2c529cd1a28f",
+						"account_id": "e0b3f39a-183d-453e-b754-0c13e5bab0b3",
+						"created_at": "2014-11-06T10:34:47.123456Z",
+						"updated_at": "2014-11-06T10:40:47.123456Z",
+						"amount": "4.23",
+						"type": "order",
+						"ref": "0a205de4-dd35-4370-a285-fe8fc375a273"
+					},
+					{
+						"id": "7b5f2e31-3f7c-4cd5-8a5a-5b1e2a93f6c4",
+						"account_id": "e0b3f39a-183d-453e-b754-0c13e5bab0b3",
+						"created_at": "2014-11-06T09:00:00.000000Z",
+						"updated_at": "2014-11-06T09:00:00.000000Z",
+						"amount": "10.00",
+						"type": "transfer",
+						"ref": "a9625b04-fc66-4999-a876-543c3684d702"
+					}
+				]
+			`,
+		),
+	)
+	client := NewMockClient()
+	output, err := GetAccountHolds(client, "e0b3f39a-183d-453e-b754-0c13e5bab0b3")
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if len(output) != 2 {
+		t.Fatalf("Expected output.length = 2, actual = %v", len(output))
+	}
+	if output[0].OrderID() != "0a205de4-dd35-4370-a285-fe8fc375a273" || output[0].TransferID() != "" {
+		t.Fatalf("Expected an order hold, actual = %v", output[0])
+	}
+	if output[1].TransferID() != "a9625b04-fc66-4999-a876-543c3684d702" || output[1].OrderID() != "" {
+		t.Fatalf("Expected a transfer hold, actual = %v", output[1])
+	}
+	summary := output.Summarize("e0b3f39a-183d-453e-b754-0c13e5bab0b3")
+	if summary.Count != 2 || summary.Total.String() != "14.23" || summary.Orders.String() != "4.23" || summary.Transfers.String() != "10.00" {
+		t.Fatalf("Expected 2 holds totalling 14.23, actual = %v", summary)
+	}
+	account := Account{Balance: MustDecimal("100.00"), Available: MustDecimal("85.00")}
+	if unexplained := summary.Unexplained(account); unexplained.String() != "0.77" {
+		t.Fatalf("Expected 0.77 unexplained, actual = %v", unexplained)
+	}
+}
+
+func Test_SummarizeAccountHolds(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/accounts/e0b3f39a-183d-453e-b754-0c13e5bab0b3/holds",
+		func(req *http.Request) (*http.Response, error) {
+			if req.URL.Query().Get("after") == "" {
+				res := httpmock.NewStringResponse(200, `[
+					{ "id": "1", "amount": "1.5", "type": "order", "ref": "o1" },
+					{ "id": "2", "amount": "2.5", "type": "order", "ref": "o2" }
+				]`)
+				res.Header.Set("CB-AFTER", "2")
+				return res, nil
+			}
+			return httpmock.NewStringResponse(200, `[
+				{ "id": "3", "amount": "3", "type": "transfer", "ref": "t1" }
+			]`), nil
+		},
+	)
+	client := NewMockClient()
+	summary, err := SummarizeAccountHolds(client, "e0b3f39a-183d-453e-b754-0c13e5bab0b3")
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if summary.Count != 3 || !summary.Total.Equal(MustDecimal("7")) || !summary.Orders.Equal(MustDecimal("4")) || !summary.Transfers.Equal(MustDecimal("3")) {
+		t.Fatalf("Expected 3 holds totalling 7, actual = %v", summary)
+	}
+}
@@ -1,10 +1,10 @@
 package clients
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
-	"strconv"
 	"time"
 )
 
@@ -13,16 +13,20 @@ import (
 //
 
 type GdaxProductItem struct {
-	ID             string `json:"id"`
-	BaseCurrency   string `json:"base_currency"`
-	QuoteCurrency  string `json:"quote_currency"`
-	BaseMinSize    string `json:"base_min_size"`
-	BaseMaxSize    string `json:"base_max_size"`
-	QuoteIncrement string `json:"quote_increment"`
+	ID             string  `json:"id"`
+	BaseCurrency   string  `json:"base_currency"`
+	QuoteCurrency  string  `json:"quote_currency"`
+	BaseMinSize    Decimal `json:"base_min_size"`
+	BaseMaxSize    Decimal `json:"base_max_size"`
+	QuoteIncrement Decimal `json:"quote_increment"`
 }
 type GdaxProductsResponse []GdaxProductItem
 
 func GetProducts(client *Client) (GdaxProductsResponse, error) {
+	return GetProductsContext(context.Background(), client)
+}
+
+func GetProductsContext(ctx context.Context, client *Client) (GdaxProductsResponse, error) {
 	// Get Products
 	// Get a list of available currency pairs for trading.
 	//
@@ -47,7 +51,7 @@ func GetProducts(client *Client) (GdaxProductsResponse, error) {
 	// The order price must be a multiple of this increment (i.e. if the increment is 0.01, order prices of 0.001 or 0.021 would be rejected).
 	//
 	output := []GdaxProductItem{}
-	_, err := client.Get("/products", url.Values{}, &output)
+	_, err := client.GetContext(ctx, "/products", url.Values{}, &output)
 	return output, err
 }
 
@@ -61,6 +65,10 @@ type GdaxTimeResponse struct {
 }
 
 func GetTime(client *Client) (*GdaxTimeResponse, error) {
+	return GetTimeContext(context.Background(), client)
+}
+
+func GetTimeContext(ctx context.Context, client *Client) (*GdaxTimeResponse, error) {
 	// Get the API server time.
 	// HTTP REQUEST
 	//  GET /time
@@ -74,7 +82,7 @@ func GetTime(client *Client) (*GdaxTimeResponse, error) {
 	//  The epoch field represents decimal seconds since Unix Epoch
 	//
 	output := &GdaxTimeResponse{}
-	_, err := client.Get("/time", url.Values{}, output)
+	_, err := client.GetContext(ctx, "/time", url.Values{}, output)
 	return output, err
 }
 
@@ -85,11 +93,15 @@ func GetTime(client *Client) (*GdaxTimeResponse, error) {
 type GdaxCurrency struct {
 	ID      string  `json:"id"`
 	Name    string  `json:"name"`
-	MinSize float64 `json:"min_size"`
+	MinSize Decimal `json:"min_size"`
 }
 type GdaxCurrenciesResponse []GdaxCurrency
 
 func GetCurrencies(client *Client) (GdaxCurrenciesResponse, error) {
+	return GetCurrenciesContext(context.Background(), client)
+}
+
+func GetCurrenciesContext(ctx context.Context, client *Client) (GdaxCurrenciesResponse, error) {
 	// Currencies
 	// Get currencies
 	// List known currencies.
@@ -112,28 +124,12 @@ func GetCurrencies(client *Client) (GdaxCurrenciesResponse, error) {
 	// Code  Description
 	// BTC Bitcoin
 	//
-
-	type AutoGeneratedResponse struct {
-		ID      string `json:"id"`
-		Name    string `json:"name"`
-		MinSize string `json:"min_size"`
-	}
-	tmp := []AutoGeneratedResponse{}
-	_, err := client.Get("/currencies", url.Values{}, &tmp)
-
 	output := []GdaxCurrency{}
-	for _, row := range tmp {
-		min_size, err := strconv.ParseFloat(row.MinSize, 64)
-		if nil != err {
-			return []GdaxCurrency{}, err
-		}
-		output = append(output, GdaxCurrency{
-			ID:      row.ID,
-			Name:    row.Name,
-			MinSize: min_size,
-		})
+	_, err := client.GetContext(ctx, "/currencies", url.Values{}, &output)
+	if nil != err {
+		return []GdaxCurrency{}, err
 	}
-	return output, err
+	return output, nil
 }
 
 //
@@ -141,15 +137,19 @@ func GetCurrencies(client *Client) (GdaxCurrenciesResponse, error) {
 //
 
 type GdaxProduct24HrStatsResponse struct {
-	Open        float64 `json:"open"`
-	High        float64 `json:"high"`
-	Low         float64 `json:"low"`
-	Last        float64 `json:"last"`
-	Volume      float64 `json:"volume"`
-	Volume30Day float64 `json:"volume,omitempty"`
+	Open        Decimal `json:"open"`
+	High        Decimal `json:"high"`
+	Low         Decimal `json:"low"`
+	Last        Decimal `json:"last"`
+	Volume      Decimal `json:"volume"`
+	Volume30Day Decimal `json:"volume_30day"`
 }
 
 func GetProduct24HrStats(client *Client, product_id string) (*GdaxProduct24HrStatsResponse, error) {
+	return GetProduct24HrStatsContext(context.Background(), client, product_id)
+}
+
+func GetProduct24HrStatsContext(ctx context.Context, client *Client, product_id string) (*GdaxProduct24HrStatsResponse, error) {
 	// Get 24hr Stats
 	// Get 24 hr stats for the product. volume is in base currency units. open, high, low are in quote currency units.
 	//
@@ -163,51 +163,12 @@ func GetProduct24HrStats(client *Client, product_id string) (*GdaxProduct24HrSta
 	//     "volume": "2.41000000"
 	// }
 	//
-	type AutoGeneratedResponse struct {
-		Open        string `json:"open"`
-		High        string `json:"high"`
-		Low         string `json:"low"`
-		Volume      string `json:"volume"`
-		Last        string `json:"last"`
-		Volume30Day string `json:"volume_30day"`
-	}
-
-	tmp := &AutoGeneratedResponse{}
-	_, err := client.Get(fmt.Sprintf("/products/%s/stats", product_id), url.Values{}, tmp)
-
-	open, err := strconv.ParseFloat(tmp.Open, 64)
-	if nil != err {
-		return nil, err
-	}
-	high, err := strconv.ParseFloat(tmp.High, 64)
+	output := &GdaxProduct24HrStatsResponse{}
+	_, err := client.GetContext(ctx, fmt.Sprintf("/products/%s/stats", product_id), url.Values{}, output)
 	if nil != err {
 		return nil, err
 	}
-	low, err := strconv.ParseFloat(tmp.Low, 64)
-	if nil != err {
-		return nil, err
-	}
-	volume, err := strconv.ParseFloat(tmp.Volume, 64)
-	if nil != err {
-		return nil, err
-	}
-	last, err := strconv.ParseFloat(tmp.Last, 64)
-	if nil != err {
-		return nil, err
-	}
-	volume_30day, err := strconv.ParseFloat(tmp.Volume30Day, 64)
-	if nil != err && tmp.Volume30Day != "" {
-		return nil, err
-	}
-	output := &GdaxProduct24HrStatsResponse{
-		Open:        open,
-		High:        high,
-		Low:         low,
-		Volume:      volume,
-		Last:        last,
-		Volume30Day: volume_30day,
-	}
-	return output, err
+	return output, nil
 }
 
 //
@@ -216,11 +177,11 @@ func GetProduct24HrStats(client *Client, product_id string) (*GdaxProduct24HrSta
 
 type GdaxProductHistoricRate struct {
 	Time   time.Time `json:"0"`
-	Low    float64   `json:"1"`
-	High   float64   `json:"2"`
-	Open   float64   `json:"3"`
-	Close  float64   `json:"4"`
-	Volume float64   `json:"5"`
+	Low    Decimal   `json:"1"`
+	High   Decimal   `json:"2"`
+	Open   Decimal   `json:"3"`
+	Close  Decimal   `json:"4"`
+	Volume Decimal   `json:"5"`
 }
 type GdaxProductHistoricRatesResponse []GdaxProductHistoricRate
 
@@ -241,6 +202,10 @@ const (
 )
 
 func GetProductHistoricRates(client *Client, product_id string, start *time.Time, end *time.Time, granularity HistoricRateGranularity) (GdaxProductHistoricRatesResponse, error) {
+	return GetProductHistoricRatesContext(context.Background(), client, product_id, start, end, granularity)
+}
+
+func GetProductHistoricRatesContext(ctx context.Context, client *Client, product_id string, start *time.Time, end *time.Time, granularity HistoricRateGranularity) (GdaxProductHistoricRatesResponse, error) {
 	// Get Historic Rates
 	// Historic rates for a product. Rates are returned in grouped buckets based on requested granularity.
 	//
@@ -280,28 +245,39 @@ func GetProductHistoricRates(client *Client, product_id string, start *time.Time
 		args["end"] = []string{end.Format("2006-01-02T15:04:05Z")}
 	}
 	tmp := [][]json.Number{}
-	_, err := client.Get(fmt.Sprintf("/products/%s/candles", product_id), args, &tmp)
+	_, err := client.GetContext(ctx, fmt.Sprintf("/products/%s/candles", product_id), args, &tmp)
+	if nil != err {
+		return GdaxProductHistoricRatesResponse{}, err
+	}
 
 	output := make([]GdaxProductHistoricRate, 0, len(tmp))
 	for _, row := range tmp {
-		timeSeconds, _ := row[0].Int64()
-		low, _ := row[1].Float64()
-		high, _ := row[2].Float64()
-		open, _ := row[3].Float64()
-		close, _ := row[4].Float64()
-		volume, _ := row[5].Float64()
+		if len(row) < 6 {
+			return GdaxProductHistoricRatesResponse{}, fmt.Errorf("clients: invalid candle %v", row)
+		}
+		timeSeconds, err := row[0].Int64()
+		if nil != err {
+			return GdaxProductHistoricRatesResponse{}, err
+		}
+		values := make([]Decimal, 5)
+		for i := range values {
+			values[i], err = NewDecimalFromString(row[i+1].String())
+			if nil != err {
+				return GdaxProductHistoricRatesResponse{}, err
+			}
+		}
 
 		item := GdaxProductHistoricRate{
 			Time:   time.Unix(timeSeconds, 0),
-			Low:    low,
-			High:   high,
-			Open:   open,
-			Close:  close,
-			Volume: volume,
+			Low:    values[0],
+			High:   values[1],
+			Open:   values[2],
+			Close:  values[3],
+			Volume: values[4],
 		}
 		output = append(output, item)
 	}
-	return output, err
+	return output, nil
 }
 
 //
@@ -311,13 +287,26 @@ func GetProductHistoricRates(client *Client, product_id string, start *time.Time
 type GdaxProductTrade struct {
 	Time    time.Time `json:"time"`
 	TradeID int       `json:"trade_id"`
-	Price   float64   `json:"price"`
-	Size    float64   `json:"size"`
+	Price   Decimal   `json:"price"`
+	Size    Decimal   `json:"size"`
 	Side    string    `json:"side"`
 }
 type GdaxProductTradesResponse []GdaxProductTrade
 
 func GetProductTrades(client *Client, product_id string) (GdaxProductTradesResponse, error) {
+	return GetProductTradesContext(context.Background(), client, product_id)
+}
+
+func GetProductTradesContext(ctx context.Context, client *Client, product_id string) (GdaxProductTradesResponse, error) {
+	output, _, err := GetProductTradesPageContext(ctx, client, product_id, Pagination{})
+	return output, err
+}
+
+func GetProductTradesPage(client *Client, product_id string, pagination Pagination) (GdaxProductTradesResponse, Cursor, error) {
+	return GetProductTradesPageContext(context.Background(), client, product_id, pagination)
+}
+
+func GetProductTradesPageContext(ctx context.Context, client *Client, product_id string, pagination Pagination) (GdaxProductTradesResponse, Cursor, error) {
 	// Get Trades
 	// List the latest trades for a product.
 	//
@@ -342,36 +331,25 @@ func GetProductTrades(client *Client, product_id string) (GdaxProductTradesRespo
 	// SIDE
 	// The trade side indicates the maker order side. The maker order is the order that was open on the order book. buy side indicates a down-tick because the maker was a buy order and their order was removed. Conversely, sell side indicates an up-tick.
 	//
-	type AutoGeneratedResponse struct {
-		Time    time.Time `json:"time"`
-		TradeID int       `json:"trade_id"`
-		Price   string    `json:"price"`
-		Size    string    `json:"size"`
-		Side    string    `json:"side"`
-	}
-	tmp := []AutoGeneratedResponse{}
-	_, err := client.Get(fmt.Sprintf("/products/%s/trades", product_id), url.Values{}, &tmp)
-
-	output := []GdaxProductTrade{}
-	for _, row := range tmp {
-		price, err := strconv.ParseFloat(row.Price, 64)
-		if nil != err {
-			return []GdaxProductTrade{}, err
-		}
-		size, err := strconv.ParseFloat(row.Size, 64)
-		if nil != err {
-			return []GdaxProductTrade{}, err
-		}
-		output = append(output, GdaxProductTrade{
-			Time:    row.Time,
-			TradeID: row.TradeID,
-			Price:   price,
-			Size:    size,
-			Side:    row.Side,
-		})
+	// PAGINATION
+	// This request is paginated, the cursors of the returned page are read from the CB-BEFORE and CB-AFTER headers.
+	//
+	args := url.Values{}
+	pagination.apply(args)
+	output := GdaxProductTradesResponse{}
+	res, err := client.GetContext(ctx, fmt.Sprintf("/products/%s/trades", product_id), args, &output)
+	if nil != err {
+		return GdaxProductTradesResponse{}, Cursor{}, err
 	}
+	return output, cursorFromResponse(res), nil
+}
 
-	return output, err
+/*
+	Walk the trades of a product page by page, from the latest towards the oldest.
+	Pages are decoded into a *GdaxProductTradesResponse.
+*/
+func NewProductTradesPaginator(client *Client, product_id string, pagination Pagination) *Paginator {
+	return NewPaginator(client, fmt.Sprintf("/products/%s/trades", product_id), url.Values{}, pagination)
 }
 
 //
@@ -380,15 +358,19 @@ func GetProductTrades(client *Client, product_id string) (GdaxProductTradesRespo
 
 type GdaxProductTickerResponse struct {
 	TradeID int       `json:"trade_id"`
-	Price   float64   `json:"price"`
-	Size    float64   `json:"size"`
-	Bid     float64   `json:"bid"`
-	Ask     float64   `json:"ask"`
-	Volume  float64   `json:"volume"`
+	Price   Decimal   `json:"price"`
+	Size    Decimal   `json:"size"`
+	Bid     Decimal   `json:"bid"`
+	Ask     Decimal   `json:"ask"`
+	Volume  Decimal   `json:"volume"`
 	Time    time.Time `json:"time"`
 }
 
 func GetProductTicker(client *Client, product_id string) (*GdaxProductTickerResponse, error) {
+	return GetProductTickerContext(context.Background(), client, product_id)
+}
+
+func GetProductTickerContext(ctx context.Context, client *Client, product_id string) (*GdaxProductTickerResponse, error) {
 	// Get Product Ticker
 	// Snapshot information about the last trade (tick), best bid/ask and 24h volume.
 	//
@@ -409,54 +391,12 @@ func GetProductTicker(client *Client, product_id string) (*GdaxProductTickerResp
 	//
 	// Polling is discouraged in favor of connecting via the websocket stream and listening for match messages.
 	//
-
-	type AutoGeneratedResponse struct {
-		TradeID int       `json:"trade_id"`
-		Price   string    `json:"price"`
-		Size    string    `json:"size"`
-		Bid     string    `json:"bid"`
-		Ask     string    `json:"ask"`
-		Volume  string    `json:"volume"`
-		Time    time.Time `json:"time"`
-	}
-	tmp := &AutoGeneratedResponse{}
-	_, err := client.Get(fmt.Sprintf("/products/%s/ticker", product_id), url.Values{}, tmp)
-
-	output := &GdaxProductTickerResponse{
-		TradeID: tmp.TradeID,
-		Price:   0.0,
-		Size:    0.0,
-		Bid:     0.0,
-		Ask:     0.0,
-		Volume:  0.0,
-		Time:    tmp.Time,
-	}
-	if price, err := strconv.ParseFloat(tmp.Price, 64); err != nil && tmp.Price != "" {
-		return nil, err
-	} else {
-		output.Price = price
-	}
-	if size, err := strconv.ParseFloat(tmp.Size, 64); err != nil && tmp.Size != "" {
-		return nil, err
-	} else {
-		output.Size = size
-	}
-	if bid, err := strconv.ParseFloat(tmp.Bid, 64); err != nil && tmp.Bid != "" {
-		return nil, err
-	} else {
-		output.Bid = bid
-	}
-	if ask, err := strconv.ParseFloat(tmp.Ask, 64); err != nil && tmp.Ask != "" {
-		return nil, err
-	} else {
-		output.Ask = ask
-	}
-	if volume, err := strconv.ParseFloat(tmp.Volume, 64); err != nil && tmp.Volume != "" {
+	output := &GdaxProductTickerResponse{}
+	_, err := client.GetContext(ctx, fmt.Sprintf("/products/%s/ticker", product_id), url.Values{}, output)
+	if nil != err {
 		return nil, err
-	} else {
-		output.Volume = volume
 	}
-	return output, err
+	return output, nil
 }
 
 //
@@ -464,13 +404,13 @@ func GetProductTicker(client *Client, product_id string) (*GdaxProductTickerResp
 //
 
 type GdaxProductOrderBookItemAggregated struct {
-	Price     float64
-	Size      float64
+	Price     Decimal
+	Size      Decimal
 	NumOrders int64
 }
 type GdaxProductOrderBookItemNonAggregated struct {
-	Price   float64
-	Size    float64
+	Price   Decimal
+	Size    Decimal
 	OrderId string
 }
 type GdaxProductOrderBookResponseLevel1 struct {
@@ -490,11 +430,15 @@ type GdaxProductOrderBookResponseLevel3 struct {
 }
 
 func GetProductOrderBookLevel1(client *Client, product_id string) (*GdaxProductOrderBookResponseLevel1, error) {
+	return GetProductOrderBookLevel1Context(context.Background(), client, product_id)
+}
+
+func GetProductOrderBookLevel1Context(ctx context.Context, client *Client, product_id string) (*GdaxProductOrderBookResponseLevel1, error) {
 	args := url.Values{
 		"level": []string{"1"},
 	}
 	tmp := map[string]interface{}{}
-	_, err := client.Get(fmt.Sprintf("/products/%s/book", product_id), args, &tmp)
+	_, err := client.GetContext(ctx, fmt.Sprintf("/products/%s/book", product_id), args, &tmp)
 	if nil != err {
 		return nil, err
 	}
@@ -526,11 +470,18 @@ func GetProductOrderBookLevel1(client *Client, product_id string) (*GdaxProductO
 }
 
 func GetProductOrderBookLevel2(client *Client, product_id string) (*GdaxProductOrderBookResponseLevel2, error) {
+	return GetProductOrderBookLevel2Context(context.Background(), client, product_id)
+}
+
+func GetProductOrderBookLevel2Context(ctx context.Context, client *Client, product_id string) (*GdaxProductOrderBookResponseLevel2, error) {
 	args := url.Values{
 		"level": []string{"2"},
 	}
 	tmp := map[string]interface{}{}
-	_, err := client.Get(fmt.Sprintf("/products/%s/book", product_id), args, &tmp)
+	_, err := client.GetContext(ctx, fmt.Sprintf("/products/%s/book", product_id), args, &tmp)
+	if nil != err {
+		return nil, err
+	}
 
 	sequence := int64(tmp["sequence"].(float64))
 	bids := tmp["bids"].([]interface{})
@@ -560,11 +511,18 @@ func GetProductOrderBookLevel2(client *Client, product_id string) (*GdaxProductO
 }
 
 func GetProductOrderBookLevel3(client *Client, product_id string) (*GdaxProductOrderBookResponseLevel3, error) {
+	return GetProductOrderBookLevel3Context(context.Background(), client, product_id)
+}
+
+func GetProductOrderBookLevel3Context(ctx context.Context, client *Client, product_id string) (*GdaxProductOrderBookResponseLevel3, error) {
 	args := url.Values{
 		"level": []string{"3"},
 	}
 	tmp := map[string]interface{}{}
-	_, err := client.Get(fmt.Sprintf("/products/%s/book", product_id), args, &tmp)
+	_, err := client.GetContext(ctx, fmt.Sprintf("/products/%s/book", product_id), args, &tmp)
+	if nil != err {
+		return nil, err
+	}
 
 	sequence := int64(tmp["sequence"].(float64))
 	bids := tmp["bids"].([]interface{})
@@ -596,12 +554,12 @@ func GetProductOrderBookLevel3(client *Client, product_id string) (*GdaxProductO
 func parseGdaxProductOrderBookItemAggregated(raw_row interface{}) (*GdaxProductOrderBookItemAggregated, error) {
 	row := raw_row.([]interface{})
 	price, _ := row[0].(string)
-	priceFloat64, err := strconv.ParseFloat(price, 64)
+	priceDecimal, err := NewDecimalFromString(price)
 	if err != nil {
 		return nil, err
 	}
 	size, _ := row[1].(string)
-	sizeFloat64, err := strconv.ParseFloat(size, 64)
+	sizeDecimal, err := NewDecimalFromString(size)
 	if err != nil {
 		return nil, err
 	}
@@ -609,8 +567,8 @@ func parseGdaxProductOrderBookItemAggregated(raw_row interface{}) (*GdaxProductO
 	numOrdersInt64 := int64(numOrders)
 
 	return &GdaxProductOrderBookItemAggregated{
-		Price:     priceFloat64,
-		Size:      sizeFloat64,
+		Price:     priceDecimal,
+		Size:      sizeDecimal,
 		NumOrders: numOrdersInt64,
 	}, nil
 }
@@ -618,20 +576,20 @@ func parseGdaxProductOrderBookItemAggregated(raw_row interface{}) (*GdaxProductO
 func parseGdaxProductOrderBookItemNonAggregated(raw_row interface{}) (*GdaxProductOrderBookItemNonAggregated, error) {
 	row := raw_row.([]interface{})
 	price, _ := row[0].(string)
-	priceFloat64, err := strconv.ParseFloat(price, 64)
+	priceDecimal, err := NewDecimalFromString(price)
 	if err != nil {
 		return nil, err
 	}
 	size, _ := row[1].(string)
-	sizeFloat64, err := strconv.ParseFloat(size, 64)
+	sizeDecimal, err := NewDecimalFromString(size)
 	if err != nil {
 		return nil, err
 	}
 	orderId := row[2].(string)
 
 	return &GdaxProductOrderBookItemNonAggregated{
-		Price:   priceFloat64,
-		Size:    sizeFloat64,
+		Price:   priceDecimal,
+		Size:    sizeDecimal,
 		OrderId: orderId,
 	}, nil
 }
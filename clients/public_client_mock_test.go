@@ -57,13 +57,13 @@ func Test_mock_GetProducts(t *testing.T) {
 	if item.QuoteCurrency != "USD" {
 		t.Fatalf("Expected item.quote_currency = USD, actual = %v", item.QuoteCurrency)
 	}
-	if item.BaseMinSize != "0.01" {
+	if item.BaseMinSize.String() != "0.01" {
 		t.Fatalf("Expected item.base_min_size = 0.01, actual = %v", item.BaseMinSize)
 	}
-	if item.BaseMaxSize != "10000.00" {
+	if item.BaseMaxSize.String() != "10000.00" {
 		t.Fatalf("Expected item.base_max_size = 10000.00, actual = %v", item.BaseMaxSize)
 	}
-	if item.QuoteIncrement != "0.01" {
+	if item.QuoteIncrement.String() != "0.01" {
 		t.Fatalf("Expected item.quote_increment = 0.01, actual = %v", item.QuoteIncrement)
 	}
 }
@@ -131,8 +131,8 @@ func Test_mock_GetCurrencies(t *testing.T) {
 	)
 	client := NewMockClient()
 	expected := []GdaxCurrency{
-		GdaxCurrency{ID: "BTC", Name: "Bitcoin", MinSize: 0.00000001},
-		GdaxCurrency{ID: "USD", Name: "United States Dollar", MinSize: 0.01000000},
+		GdaxCurrency{ID: "BTC", Name: "Bitcoin", MinSize: MustDecimal("0.00000001")},
+		GdaxCurrency{ID: "USD", Name: "United States Dollar", MinSize: MustDecimal("0.01000000")},
 	}
 	output, err := GetCurrencies(client)
 	if err != nil {
@@ -179,12 +179,12 @@ func Test_mock_GetProduct24HrStats(t *testing.T) {
 	)
 	client := NewMockClient()
 	expected := &GdaxProduct24HrStatsResponse{
-		Open:        2000.00000000,
-		High:        2110.06000000,
-		Low:         1758.20000000,
-		Volume:      20465.01966891,
-		Last:        1893.91000000,
-		Volume30Day: 398368.6657624,
+		Open:        MustDecimal("2000.00000000"),
+		High:        MustDecimal("2110.06000000"),
+		Low:         MustDecimal("1758.20000000"),
+		Volume:      MustDecimal("20465.01966891"),
+		Last:        MustDecimal("1893.91000000"),
+		Volume30Day: MustDecimal("398368.6657624"),
 	}
 	output, err := GetProduct24HrStats(client, "BTC-USD")
 	if err != nil {
@@ -237,19 +237,19 @@ func Test_mock_GetProductHistoricRates(t *testing.T) {
 	if item.Time != time.Unix(1500130020, 0) {
 		t.Fatalf("Expected item.Time = 1500130020, actual = %v", item.Time)
 	}
-	if item.Low != 181.8 {
+	if !item.Low.Equal(MustDecimal("181.8")) {
 		t.Fatalf("Expected item.Low = 181.8, actual = %v", item.Low)
 	}
-	if item.High != 181.81 {
+	if !item.High.Equal(MustDecimal("181.81")) {
 		t.Fatalf("Expected item.High = 181.81, actual = %v", item.High)
 	}
-	if item.Open != 181.8 {
+	if !item.Open.Equal(MustDecimal("181.8")) {
 		t.Fatalf("Expected item.Open = 181.8, actual = %v", item.Open)
 	}
-	if item.Close != 181.81 {
+	if !item.Close.Equal(MustDecimal("181.81")) {
 		t.Fatalf("Expected item.Close = 181.81, actual = %v", item.Close)
 	}
-	if item.Volume != 11.34496359 {
+	if !item.Volume.Equal(MustDecimal("11.34496359")) {
 		t.Fatalf("Expected item.Volume = 11.34496359, actual = %v", item.Volume)
 	}
 }
@@ -304,10 +304,10 @@ func Test_mock_GetProductTrades(t *testing.T) {
 	if item.TradeID != 74 {
 		t.Fatalf("Expected item.TradeID = 74, actual = %v", item.TradeID)
 	}
-	if item.Price != 10.00000000 {
+	if !item.Price.Equal(MustDecimal("10.00000000")) {
 		t.Fatalf("Expected item.Price = 10.00000000, actual = %v", item.Price)
 	}
-	if item.Size != 0.01000000 {
+	if !item.Size.Equal(MustDecimal("0.01000000")) {
 		t.Fatalf("Expected item.Size = 0.01000000, actual = %v", item.Size)
 	}
 	if item.Side != "buy" {
@@ -345,11 +345,11 @@ func Test_mock_GetProductTicker(t *testing.T) {
 	client := NewMockClient()
 	expected := &GdaxProductTickerResponse{
 		TradeID: 4729088,
-		Price:   333.99,
-		Size:    0.193,
-		Bid:     333.98,
-		Ask:     333.99,
-		Volume:  5957.11914015,
+		Price:   MustDecimal("333.99"),
+		Size:    MustDecimal("0.193"),
+		Bid:     MustDecimal("333.98"),
+		Ask:     MustDecimal("333.99"),
+		Volume:  MustDecimal("5957.11914015"),
 		Time:    time.Date(2015, 11, 14, 20, 46, 03, 511254, time.UTC),
 	}
 	output, err := GetProductTicker(client, "BTC-USD")
@@ -366,19 +366,19 @@ func Test_mock_GetProductTicker(t *testing.T) {
 	if output.TradeID != expected.TradeID {
 		t.Fatalf("Expected output.TradeId = 4729088, actual = %v", output.TradeID)
 	}
-	if output.Price != expected.Price {
+	if !output.Price.Equal(expected.Price) {
 		t.Fatalf("Expected output.Price = 333.99, actual = %v", output.Price)
 	}
-	if output.Size != expected.Size {
+	if !output.Size.Equal(expected.Size) {
 		t.Fatalf("Expected output.Size = 0.193, actual = %v", output.Size)
 	}
-	if output.Bid != expected.Bid {
+	if !output.Bid.Equal(expected.Bid) {
 		t.Fatalf("Expected output.Bid = 333.98, actual = %v", output.Bid)
 	}
-	if output.Ask != expected.Ask {
+	if !output.Ask.Equal(expected.Ask) {
 		t.Fatalf("Expected output.Ask = 333.99, actual = %v", output.Ask)
 	}
-	if output.Volume != expected.Volume {
+	if !output.Volume.Equal(expected.Volume) {
 		t.Fatalf("Expected output.Volume = 5957.11914015, actual = %v", output.Volume)
 	}
 }
@@ -417,19 +417,19 @@ func Test_mock_GetProductOrderBookLevel1(t *testing.T) {
 	if output.Sequence != 775966773 {
 		t.Fatalf("Expected output.Sequence = 775966773, actual = %v", output.Sequence)
 	}
-	if output.Bid.Price != 180.79 {
+	if !output.Bid.Price.Equal(MustDecimal("180.79")) {
 		t.Fatalf("Expected output.Bid.Price = 180.79, actual = %v", output.Bid.Price)
 	}
-	if output.Bid.Size != 142.55091057 {
+	if !output.Bid.Size.Equal(MustDecimal("142.55091057")) {
 		t.Fatalf("Expected output.Bid.Size = 142.55091057, actual = %v", output.Bid.Size)
 	}
 	if output.Bid.NumOrders != 2 {
 		t.Fatalf("Expected output.Bid.NumOrders = 2, actual = %v", output.Bid.NumOrders)
 	}
-	if output.Ask.Price != 180.84 {
+	if !output.Ask.Price.Equal(MustDecimal("180.84")) {
 		t.Fatalf("Expected output.Ask.Price = 180.84, actual = %v", output.Ask.Price)
 	}
-	if output.Ask.Size != 9.91691592 {
+	if !output.Ask.Size.Equal(MustDecimal("9.91691592")) {
 		t.Fatalf("Expected output.Ask.Size = 9.91691592, actual = %v", output.Ask.Size)
 	}
 	if output.Ask.NumOrders != 2 {
@@ -574,19 +574,19 @@ func Test_mock_GetProductOrderBookLevel2(t *testing.T) {
 	if len(output.Asks) != 50 {
 		t.Fatalf("Expected output.Asks.length = 50, actual = %v", len(output.Asks))
 	}
-	if output.Bids[0].Price != 179.32 {
+	if !output.Bids[0].Price.Equal(MustDecimal("179.32")) {
 		t.Fatalf("Expected output.Bid.Price = 179.32, actual = %v", output.Bids[0].Price)
 	}
-	if output.Bids[0].Size != 45.346 {
+	if !output.Bids[0].Size.Equal(MustDecimal("45.346")) {
 		t.Fatalf("Expected output.Bid.Size = 45.346, actual = %v", output.Bids[0].Size)
 	}
 	if output.Bids[0].NumOrders != 4 {
 		t.Fatalf("Expected output.Bid.NumOrders = 4, actual = %v", output.Bids[0].NumOrders)
 	}
-	if output.Asks[0].Price != 179.33 {
+	if !output.Asks[0].Price.Equal(MustDecimal("179.33")) {
 		t.Fatalf("Expected output.Ask.Price = 179.33, actual = %v", output.Asks[0].Price)
 	}
-	if output.Asks[0].Size != 201.26000475 {
+	if !output.Asks[0].Size.Equal(MustDecimal("201.26000475")) {
 		t.Fatalf("Expected output.Ask.Size = 201.26000475, actual = %v", output.Asks[0].Size)
 	}
 	if output.Asks[0].NumOrders != 8 {
@@ -638,19 +638,19 @@ func Test_mock_GetProductOrderBookLevel3(t *testing.T) {
 	if len(output.Asks) != 3 {
 		t.Fatalf("Expected output.Asks.length = 50, actual = %v", len(output.Asks))
 	}
-	if output.Bids[0].Price != 179.27 {
+	if !output.Bids[0].Price.Equal(MustDecimal("179.27")) {
 		t.Fatalf("Expected output.Bid.Price = 179.27, actual = %v", output.Bids[0].Price)
 	}
-	if output.Bids[0].Size != 0.27 {
+	if !output.Bids[0].Size.Equal(MustDecimal("0.27")) {
 		t.Fatalf("Expected output.Bid.Size = 0.27, actual = %v", output.Bids[0].Size)
 	}
 	if output.Bids[0].OrderId != "b2276903-d242-445d-bc86-6ca2f65f5ed7" {
 		t.Fatalf("Expected output.Bid.OrderId = b2276903-d242-445d-bc86-6ca2f65f5ed7, actual = %v", output.Bids[0].OrderId)
 	}
-	if output.Asks[0].Price != 179.28 {
+	if !output.Asks[0].Price.Equal(MustDecimal("179.28")) {
 		t.Fatalf("Expected output.Ask.Price = 179.28, actual = %v", output.Asks[0].Price)
 	}
-	if output.Asks[0].Size != 252.43268514 {
+	if !output.Asks[0].Size.Equal(MustDecimal("252.43268514")) {
 		t.Fatalf("Expected output.Ask.Size = 252.43268514, actual = %v", output.Asks[0].Size)
 	}
 	if output.Asks[0].OrderId != "e2a982f2-5cd0-4775-ab36-08f79d622e2b" {
@@ -0,0 +1,164 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+	Rate Limits
+
+	When a rate limit is exceeded, a status of 429 Too Many Requests will be returned.
+
+	PUBLIC ENDPOINTS
+	We throttle public endpoints by IP: 3 requests per second, up to 6 requests per second in bursts.
+
+	PRIVATE ENDPOINTS
+	We throttle private endpoints by user ID: 5 requests per second, up to 10 requests per second in bursts.
+*/
+const (
+	PublicRateLimit  = 3
+	PublicRateBurst  = 6
+	PrivateRateLimit = 5
+	PrivateRateBurst = 10
+)
+
+/*
+	Returned by TokenBucket.Wait when the context deadline would pass before a token is available
+*/
+var ErrRateLimitDeadline = errors.New("clients: rate limiter wait would exceed the context deadline")
+
+/*
+	Token bucket holding up to burst tokens and refilled at rate tokens per second.
+	It is safe for concurrent use.
+*/
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+/*
+	Create a bucket allowing rate requests per second on average and up to burst requests at once.
+	The bucket starts full.
+*/
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}
+}
+
+/*
+	Block until a token is available, then take it.
+
+	Waiting callers are served in the order they called Wait. If ctx is done first, or its
+	deadline is too close to ever get a token, the reserved token is given back and the
+	context error (or ErrRateLimitDeadline) is returned.
+*/
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	delay := b.reserve()
+	if delay <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && b.now().Add(delay).After(deadline) {
+		b.cancel()
+		return ErrRateLimitDeadline
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	}
+}
+
+/*
+	Take a token, possibly going into debt, and return how long the caller must wait before using it
+*/
+func (b *TokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	b.tokens -= 1
+	if b.tokens >= 0 || b.rate <= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+/*
+	Give back a token taken by reserve that will not be used
+*/
+func (b *TokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens += 1
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+/*
+	Client side rate limiter with separate buckets for the public (per IP) and
+	private (per API key) endpoints. A nil bucket means that side is not limited.
+*/
+type RateLimiter struct {
+	Public  *TokenBucket
+	Private *TokenBucket
+}
+
+/*
+	Create a rate limiter matching the documented GDAX limits
+*/
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		Public:  NewTokenBucket(PublicRateLimit, PublicRateBurst),
+		Private: NewTokenBucket(PrivateRateLimit, PrivateRateBurst),
+	}
+}
+
+/*
+	Block until a request to pathname is allowed
+*/
+func (l *RateLimiter) Wait(ctx context.Context, pathname string) error {
+	bucket := l.Private
+	if isPublicPathname(pathname) {
+		bucket = l.Public
+	}
+	if bucket == nil {
+		return nil
+	}
+	return bucket.Wait(ctx)
+}
+
+/*
+	Market data and the server time are public, everything else requires authentication
+*/
+func isPublicPathname(pathname string) bool {
+	return pathname == "/products" ||
+		strings.HasPrefix(pathname, "/products/") ||
+		pathname == "/currencies" ||
+		pathname == "/time"
+}
@@ -0,0 +1,69 @@
+package clients
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_TokenBucket_Wait(t *testing.T) {
+	now := time.Date(2018, 01, 01, 00, 00, 00, 0, time.UTC)
+	bucket := NewTokenBucket(2, 2)
+	bucket.now = func() time.Time { return now }
+	// The burst is available immediately
+	for i := 0; i < 2; i++ {
+		if delay := bucket.reserve(); delay != 0 {
+			t.Fatalf("Expected no delay for request %d, actual = %v", i, delay)
+		}
+	}
+	// The next token is 1 / rate seconds away
+	if delay := bucket.reserve(); delay != 500*time.Millisecond {
+		t.Fatalf("Expected a 500ms delay, actual = %v", delay)
+	}
+	// After a second the debt is paid off and one token is back
+	now = now.Add(time.Second)
+	if delay := bucket.reserve(); delay != 0 {
+		t.Fatalf("Expected no delay, actual = %v", delay)
+	}
+}
+
+func Test_TokenBucket_Wait_canceled(t *testing.T) {
+	bucket := NewTokenBucket(0.001, 1)
+	if err := bucket.Wait(context.Background()); err != nil {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := bucket.Wait(ctx); err != context.Canceled {
+		t.Fatalf("Expected context.Canceled, actual = %v", err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := bucket.Wait(ctx); err != ErrRateLimitDeadline {
+		t.Fatalf("Expected ErrRateLimitDeadline, actual = %v", err)
+	}
+	if bucket.tokens > 0.01 || bucket.tokens < -0.01 {
+		t.Fatalf("Expected the canceled reservations to be given back, actual tokens = %v", bucket.tokens)
+	}
+}
+
+func Test_RateLimiter_Wait(t *testing.T) {
+	limiter := &RateLimiter{
+		Public:  NewTokenBucket(1000, 1),
+		Private: NewTokenBucket(0.001, 1),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	// Drain the private bucket, public requests must not be affected
+	if err := limiter.Wait(ctx, "/accounts"); err != nil {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	for _, pathname := range []string{"/products", "/products/BTC-USD/ticker", "/currencies", "/time"} {
+		if err := limiter.Wait(ctx, pathname); err != nil {
+			t.Fatalf("Expected %v to use the public bucket, actual = %v", pathname, err)
+		}
+	}
+	if err := limiter.Wait(ctx, "/users/self/trailing-volume"); err != ErrRateLimitDeadline {
+		t.Fatalf("Expected the private bucket to be empty, actual = %v", err)
+	}
+}
@@ -0,0 +1,297 @@
+package clients
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+	CSV reports
+
+	The csv files of fills and account reports (see CreateReport and DownloadReport) are parsed
+	into the same Fill and LedgerEntry types returned by GetFills and GetAccountLedger.
+
+	Columns are looked up by their header rather than by position, so that the layouts of the
+	different report versions are all understood, e.g. the leading "portfolio" column of newer
+	reports. Columns that are not part of the typed records (units, totals, portfolio) are ignored.
+
+	A row that cannot be parsed does not stop the parser: the other rows are returned along with
+	a ReportErrors listing every bad row.
+*/
+
+/*
+	A row of a csv report that could not be parsed
+*/
+type ReportRowError struct {
+	// Number of the record in the file, the header being record 1
+	Row    int
+	Column string
+	Err    error
+}
+
+func (e ReportRowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("clients: report row %d: %v", e.Row, e.Err)
+	}
+	return fmt.Sprintf("clients: report row %d, column %q: %v", e.Row, e.Column, e.Err)
+}
+
+/*
+	Every bad row of a csv report
+*/
+type ReportErrors []ReportRowError
+
+func (e ReportErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%v (and %d more bad rows)", e[0].Error(), len(e)-1)
+}
+
+/*
+	Columns of a report by name, names are normalised to lower case words, e.g. "Trade ID" and
+	"trade_id" are both "trade id"
+*/
+type reportColumns map[string]int
+
+func normalizeReportColumn(name string) string {
+	name = strings.TrimPrefix(name, "\ufeff")
+	return strings.ToLower(strings.TrimSpace(strings.Replace(name, "_", " ", -1)))
+}
+
+/*
+	Read the header of a report. aliases maps the names a column has in other report versions
+	to the name used by the parser.
+*/
+func readReportHeader(reader *csv.Reader, required []string, aliases map[string]string) (reportColumns, error) {
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("clients: empty report")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("clients: invalid report header: %v", err)
+	}
+	columns := reportColumns{}
+	for i, name := range header {
+		columns[normalizeReportColumn(name)] = i
+	}
+	for alias, name := range aliases {
+		if _, ok := columns[name]; ok {
+			continue
+		}
+		if i, ok := columns[alias]; ok {
+			columns[name] = i
+		}
+	}
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("clients: invalid report header, missing column %q", name)
+		}
+	}
+	return columns, nil
+}
+
+/*
+	Read the records of a csv report after its header, calling parse with every one of them
+	and collecting the row errors
+*/
+func readReportRows(r io.Reader, required []string, aliases map[string]string, parse func(row reportRow) error) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	columns, err := readReportHeader(reader, required, aliases)
+	if err != nil {
+		return err
+	}
+	errs := ReportErrors{}
+	for number := 2; ; number++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// The reader cannot resynchronise after a malformed record
+			errs = append(errs, ReportRowError{Row: number, Err: err})
+			break
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if err := parse(reportRow{number: number, columns: columns, record: record}); err != nil {
+			row_error, ok := err.(ReportRowError)
+			if !ok {
+				row_error = ReportRowError{Row: number, Err: err}
+			}
+			errs = append(errs, row_error)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+type reportRow struct {
+	number  int
+	columns reportColumns
+	record  []string
+}
+
+func (r reportRow) error(column string, err error) error {
+	return ReportRowError{Row: r.number, Column: column, Err: err}
+}
+
+/*
+	Value of column, empty when the report or the row does not have it
+*/
+func (r reportRow) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+func (r reportRow) required(column string) (string, error) {
+	value := r.get(column)
+	if value == "" {
+		return "", r.error(column, fmt.Errorf("missing value"))
+	}
+	return value, nil
+}
+
+func (r reportRow) decimal(column string) (Decimal, error) {
+	value, err := r.required(column)
+	if err != nil {
+		return Decimal{}, err
+	}
+	d, err := NewDecimalFromString(value)
+	if err != nil {
+		return Decimal{}, r.error(column, err)
+	}
+	return d, nil
+}
+
+func (r reportRow) time(column string) (time.Time, error) {
+	value, err := r.required(column)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, r.error(column, err)
+	}
+	return t, nil
+}
+
+var (
+	fillsReportColumns = []string{"trade id", "product", "side", "created at", "size", "price", "fee"}
+	fillsReportAliases = map[string]string{
+		"product id": "product",
+		"time":       "created at",
+	}
+)
+
+/*
+	Parse a fills report in csv format, e.g.
+
+		portfolio,trade id,product,side,created at,size,size unit,price,fee,total,price/fee/total unit
+		default,74,BTC-USD,BUY,2017-11-20T20:03:31.123Z,0.01000000,BTC,10000.00,0.25,-100.25,USD
+
+	Returns the fills of every valid row, and a ReportErrors when some rows are invalid.
+*/
+func ParseFillsReport(r io.Reader) (FillsResponse, error) {
+	fills := FillsResponse{}
+	err := readReportRows(r, fillsReportColumns, fillsReportAliases, func(row reportRow) error {
+		fill := Fill{}
+		trade_id, err := row.required("trade id")
+		if err != nil {
+			return err
+		}
+		if fill.TradeID, err = strconv.Atoi(trade_id); err != nil {
+			return row.error("trade id", err)
+		}
+		if fill.ProductID, err = row.required("product"); err != nil {
+			return err
+		}
+		switch side := strings.ToLower(row.get("side")); side {
+		case string(OrderSideBuy), string(OrderSideSell):
+			fill.Side = OrderSide(side)
+		default:
+			return row.error("side", fmt.Errorf("expected BUY or SELL, got %q", row.get("side")))
+		}
+		if fill.CreatedAt, err = row.time("created at"); err != nil {
+			return err
+		}
+		if fill.Size, err = row.decimal("size"); err != nil {
+			return err
+		}
+		if fill.Price, err = row.decimal("price"); err != nil {
+			return err
+		}
+		if fill.Fee, err = row.decimal("fee"); err != nil {
+			return err
+		}
+		fill.OrderID = row.get("order id")
+		fills = append(fills, fill)
+		return nil
+	})
+	return fills, err
+}
+
+var (
+	accountReportColumns = []string{"type", "time", "amount", "balance"}
+	accountReportAliases = map[string]string{
+		"created at": "time",
+		"product id": "product",
+	}
+)
+
+/*
+	Parse an account report in csv format, e.g.
+
+		portfolio,type,time,amount,balance,amount/balance unit,transfer id,trade id,order id
+		default,match,2017-11-20T20:03:31.123Z,0.01000000,0.01000000,BTC,,74,d50ec984-77a8-460a-b958-66f114b0de9b
+		default,deposit,2017-11-20T19:00:00.000Z,100.00,100.00,USD,5d3a4c88-3e1c-4d54-a9d8-7ae2d4b3d2a1,,
+
+	Deposits and withdrawals are reported as transfer entries whose TransferType is "deposit" or
+	"withdraw". Returns the entries of every valid row, and a ReportErrors when some rows are invalid.
+*/
+func ParseAccountReport(r io.Reader) (AccountLedgerResponse, error) {
+	entries := AccountLedgerResponse{}
+	err := readReportRows(r, accountReportColumns, accountReportAliases, func(row reportRow) error {
+		entry := LedgerEntry{}
+		entry_type, err := row.required("type")
+		if err != nil {
+			return err
+		}
+		switch entry_type = strings.ToLower(entry_type); entry_type {
+		case string(LedgerEntryMatch), string(LedgerEntryFee), string(LedgerEntryRebate), string(LedgerEntryTransfer):
+			entry.Type = LedgerEntryType(entry_type)
+		case "deposit", "withdraw", "withdrawal":
+			entry.Type = LedgerEntryTransfer
+			entry.Details.TransferType = strings.TrimSuffix(entry_type, "al")
+		default:
+			return row.error("type", fmt.Errorf("unknown entry type %q", entry_type))
+		}
+		if entry.CreatedAt, err = row.time("time"); err != nil {
+			return err
+		}
+		if entry.Amount, err = row.decimal("amount"); err != nil {
+			return err
+		}
+		if entry.Balance, err = row.decimal("balance"); err != nil {
+			return err
+		}
+		entry.Details.TransferID = row.get("transfer id")
+		entry.Details.TradeID = row.get("trade id")
+		entry.Details.OrderID = row.get("order id")
+		entry.Details.ProductID = row.get("product")
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries, err
+}
@@ -0,0 +1,118 @@
+package clients
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_ParseFillsReport(t *testing.T) {
+	report := "portfolio,trade id,product,side,created at,size,size unit,price,fee,total,price/fee/total unit\n" +
+		"default,74,BTC-USD,BUY,2017-11-20T20:03:31.123Z,0.01000000,BTC,10000.00,0.25,-100.25,USD\n" +
+		"default,75,BTC-USD,SELL,2017-11-20T20:04:00.000Z,0.02000000,BTC,10100.00,0.50,201.50,USD\n"
+	fills, err := ParseFillsReport(strings.NewReader(report))
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if len(fills) != 2 {
+		t.Fatalf("Expected 2 fills, actual = %v", len(fills))
+	}
+	fill := fills[0]
+	if fill.TradeID != 74 || fill.ProductID != "BTC-USD" || fill.Side != OrderSideBuy {
+		t.Fatalf("Expected trade 74 buying BTC-USD, actual = %v", fill)
+	}
+	if fill.Size.String() != "0.01000000" || fill.Price.String() != "10000.00" || fill.Fee.String() != "0.25" {
+		t.Fatalf("Expected 0.01000000 at 10000.00 with a 0.25 fee, actual = %v", fill)
+	}
+	if !fill.CreatedAt.Equal(time.Date(2017, 11, 20, 20, 3, 31, 123000000, time.UTC)) {
+		t.Fatalf("Expected created at 2017-11-20T20:03:31.123Z, actual = %v", fill.CreatedAt)
+	}
+	if fills[1].Side != OrderSideSell {
+		t.Fatalf("Expected a sell, actual = %v", fills[1].Side)
+	}
+}
+
+func Test_ParseFillsReport_OlderHeader(t *testing.T) {
+	// No portfolio column, different order and spelling
+	report := "\ufeffTrade_ID,Product,Side,Created_At,Size,Price,Fee,Total\n" +
+		"74,BTC-USD,buy,2017-11-20T20:03:31Z,0.01,10000,0.25,-100.25\n"
+	fills, err := ParseFillsReport(strings.NewReader(report))
+	if err != nil {
+		t.Fatalf("Error should be nil, %v", err)
+	}
+	if len(fills) != 1 || fills[0].TradeID != 74 || !fills[0].Price.Equal(MustDecimal("10000")) {
+		t.Fatalf("Expected trade 74 at 10000, actual = %v", fills)
+	}
+}
+
+func Test_ParseFillsReport_RowErrors(t *testing.T) {
+	report := "trade id,product,side,created at,size,price,fee\n" +
+		"74,BTC-USD,buy,2017-11-20T20:03:31Z,0.01,10000,0.25\n" +
+		"x75,BTC-USD,buy,2017-11-20T20:03:31Z,0.01,10000,0.25\n" +
+		"76,BTC-USD,hold,2017-11-20T20:03:31Z,0.01,10000,0.25\n" +
+		"77,BTC-USD,sell,2017-11-20T20:03:31Z,0.01,ten,0.25\n" +
+		"78,BTC-USD,sell,2017-11-20T20:03:31Z,0.01\n" +
+		"79,BTC-USD,sell,2017-11-20T20:03:31Z,0.01,10000,0.25\n"
+	fills, err := ParseFillsReport(strings.NewReader(report))
+	if len(fills) != 2 || fills[0].TradeID != 74 || fills[1].TradeID != 79 {
+		t.Fatalf("Expected the valid trades 74 and 79, actual = %v", fills)
+	}
+	errs, ok := err.(ReportErrors)
+	if !ok {
+		t.Fatalf("Expected ReportErrors, actual = %v", err)
+	}
+	expected := []ReportRowError{
+		{Row: 3, Column: "trade id"},
+		{Row: 4, Column: "side"},
+		{Row: 5, Column: "price"},
+		{Row: 6, Column: "price"},
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %v row errors, actual = %v", len(expected), errs)
+	}
+	for i, row_error := range errs {
+		if row_error.Row != expected[i].Row || row_error.Column != expected[i].Column {
+			t.Fatalf("Expected an error on row %v, column %v, actual = %v", expected[i].Row, expected[i].Column, row_error)
+		}
+	}
+
+	_, err = ParseFillsReport(strings.NewReader("trade id,product,side\n74,BTC-USD,buy\n"))
+	if err == nil || !strings.Contains(err.Error(), `missing column "created at"`) {
+		t.Fatalf("Expected a missing column error, actual = %v", err)
+	}
+	_, err = ParseFillsReport(strings.NewReader(""))
+	if err == nil {
+		t.Fatalf("Expected an error for an empty report")
+	}
+}
+
+func Test_ParseAccountReport(t *testing.T) {
+	report := "portfolio,type,time,amount,balance,amount/balance unit,transfer id,trade id,order id\n" +
+		"default,match,2017-11-20T20:03:31.123Z,0.01000000,0.01000000,BTC,,74,d50ec984-77a8-460a-b958-66f114b0de9b\n" +
+		"default,fee,2017-11-20T20:03:31.123Z,-0.25,99.75,USD,,74,d50ec984-77a8-460a-b958-66f114b0de9b\n" +
+		"default,deposit,2017-11-20T19:00:00.000Z,100.00,100.00,USD,5d3a4c88-3e1c-4d54-a9d8-7ae2d4b3d2a1,,\n" +
+		"default,withdrawal,2017-11-21T19:00:00.000Z,-50.00,49.75,USD,a9625b04-fc66-4999-a876-543c3684d702,,\n" +
+		"default,conversion,2017-11-21T19:00:00.000Z,-1.00,48.75,USD,,,\n"
+	entries, err := ParseAccountReport(strings.NewReader(report))
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 entries, actual = %v", entries)
+	}
+	errs, ok := err.(ReportErrors)
+	if !ok || len(errs) != 1 || errs[0].Row != 6 || errs[0].Column != "type" {
+		t.Fatalf("Expected an unknown type error on row 6, actual = %v", err)
+	}
+	match := entries[0]
+	if match.Type != LedgerEntryMatch || match.Details.TradeID != "74" || match.Details.OrderID != "d50ec984-77a8-460a-b958-66f114b0de9b" {
+		t.Fatalf("Expected the match of trade 74, actual = %v", match)
+	}
+	if entries[1].Type != LedgerEntryFee || entries[1].Amount.String() != "-0.25" || entries[1].Balance.String() != "99.75" {
+		t.Fatalf("Expected a -0.25 fee, actual = %v", entries[1])
+	}
+	deposit := entries[2]
+	if deposit.Type != LedgerEntryTransfer || deposit.Details.TransferType != "deposit" || deposit.Details.TransferID != "5d3a4c88-3e1c-4d54-a9d8-7ae2d4b3d2a1" {
+		t.Fatalf("Expected a deposit transfer, actual = %v", deposit)
+	}
+	if entries[3].Details.TransferType != "withdraw" {
+		t.Fatalf("Expected a withdraw transfer, actual = %v", entries[3])
+	}
+}
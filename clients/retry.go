@@ -0,0 +1,178 @@
+package clients
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+	Policy for retrying requests that failed with a transient error: a network error,
+	429 Too Many Requests or a 5xx status code.
+
+	Only the methods listed in Methods are retried, GET by default, since replaying a POST or
+	DELETE that reached the server could place or cancel an order twice. Every attempt is
+	signed again with a fresh CB-ACCESS-TIMESTAMP.
+*/
+type RetryPolicy struct {
+	// Total number of attempts, including the first one
+	MaxAttempts int
+	// Delay before the first retry, doubled for every following one
+	BaseDelay time.Duration
+	// Upper bound of the delay between two attempts, Retry-After headers included
+	MaxDelay time.Duration
+	// Fraction of the delay that is randomised, between 0 and 1
+	Jitter float64
+	// Methods that are safe to retry, defaults to GET only
+	Methods []string
+}
+
+/*
+	Retry GET requests up to 3 times in total, waiting 250ms then 500ms (+/- jitter)
+*/
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   250 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		Jitter:      0.5,
+		Methods:     []string{"GET"},
+	}
+}
+
+/*
+	Decide whether the failed attempt number attempt should be tried again
+*/
+func (p *RetryPolicy) shouldRetry(ctx context.Context, method string, attempt int, res *http.Response, err error) bool {
+	if p == nil || attempt >= p.MaxAttempts || ctx.Err() != nil {
+		return false
+	}
+	if !p.allowsMethod(method) {
+		return false
+	}
+	if client_error, ok := AsClientError(err); ok {
+		return client_error.StatusCode == http.StatusTooManyRequests || client_error.StatusCode >= 500
+	}
+	return isNetworkError(err)
+}
+
+/*
+	Whether err is a network error of the HTTP client, which the next attempt may not run into.
+	Errors raised before the request is sent (credentials, signature, rate limiter) and the
+	cancellation of the request's context are final.
+*/
+func isNetworkError(err error) bool {
+	if url_error, ok := err.(*url.Error); ok {
+		if url_error.Err == context.Canceled || url_error.Err == context.DeadlineExceeded {
+			return false
+		}
+		return true
+	}
+	_, ok := err.(net.Error)
+	return ok
+}
+
+func (p *RetryPolicy) allowsMethod(method string) bool {
+	methods := p.Methods
+	if len(methods) == 0 {
+		methods = []string{"GET"}
+	}
+	for _, allowed := range methods {
+		if strings.EqualFold(allowed, method) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+	Delay before the attempt following attempt. The server's Retry-After header wins when present,
+	but is clamped to MaxDelay when set: a proxy asking for an hour does not park the request
+	that long, the retry is made after MaxDelay instead.
+*/
+func (p *RetryPolicy) backoff(attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if delay, ok := parseRetryAfter(res.Header.Get("Retry-After"), time.Now()); ok {
+			if p.MaxDelay > 0 && delay > p.MaxDelay {
+				delay = p.MaxDelay
+			}
+			return delay
+		}
+	}
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		delay = time.Duration(float64(delay) * (1 - jitter + 2*jitter*rand.Float64()))
+	}
+	return delay
+}
+
+/*
+	Parse a Retry-After header, either a number of seconds or an HTTP date
+*/
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+/*
+	Sleep for delay or until ctx is done
+*/
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+/*
+	Statistics about a single call, filled in when the context was created with WithRequestStats
+*/
+type RequestStats struct {
+	// Number of attempts made, 1 when the request was not retried
+	Attempts int
+}
+
+type requestStatsKey struct{}
+
+/*
+	Return a context that collects the statistics of the request made with it into stats
+*/
+func WithRequestStats(ctx context.Context, stats *RequestStats) context.Context {
+	return context.WithValue(ctx, requestStatsKey{}, stats)
+}
+
+func requestStatsFromContext(ctx context.Context) *RequestStats {
+	stats, _ := ctx.Value(requestStatsKey{}).(*RequestStats)
+	return stats
+}
@@ -0,0 +1,190 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"gopkg.in/jarcoal/httpmock.v1"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_Client_RetryPolicy(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	// Fail twice, then succeed
+	timestamps := []string{}
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/time",
+		func(req *http.Request) (*http.Response, error) {
+			timestamps = append(timestamps, req.Header.Get("CB-ACCESS-TIMESTAMP"))
+			switch len(timestamps) {
+			case 1:
+				return httpmock.NewStringResponse(503, `{ "message": "Service Unavailable" }`), nil
+			case 2:
+				res := httpmock.NewStringResponse(429, `{ "message": "Rate limit exceeded" }`)
+				res.Header.Set("Retry-After", "0")
+				return res, nil
+			}
+			return httpmock.NewStringResponse(200, `{ "iso": "2015-01-07T23:47:25.201Z", "epoch": 1420674445.201 }`), nil
+		},
+	)
+	client := NewMockClient()
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	stats := &RequestStats{}
+	output, err := GetTimeContext(WithRequestStats(context.Background(), stats), client)
+	if nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if output.Epoch != 1420674445.201 {
+		t.Fatalf("Expected output.Epoch = 1420674445.201, actual = %v", output.Epoch)
+	}
+	if stats.Attempts != 3 {
+		t.Fatalf("Expected 3 attempts, actual = %v", stats.Attempts)
+	}
+	for i, timestamp := range timestamps {
+		if timestamp == "" {
+			t.Fatalf("Expected attempt %d to be signed", i+1)
+		}
+	}
+}
+
+func Test_Client_RetryPolicy_exhausted(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/time",
+		httpmock.NewStringResponder(500, `{ "message": "Internal Server Error" }`),
+	)
+	client := NewMockClient()
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+	_, err := GetTime(client)
+	client_error, ok := AsClientError(err)
+	if !ok {
+		t.Fatalf("Expected a ClientError, actual = %v", err)
+	}
+	if client_error.Attempts != 2 {
+		t.Fatalf("Expected 2 attempts, actual = %v", client_error.Attempts)
+	}
+}
+
+func Test_Client_RetryPolicy_notIdempotent(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	calls := 0
+	httpmock.RegisterResponder(
+		"POST",
+		"https://mock-api.gdax.com/orders",
+		func(req *http.Request) (*http.Response, error) {
+			calls += 1
+			return httpmock.NewStringResponse(503, `{ "message": "Service Unavailable" }`), nil
+		},
+	)
+	client := NewMockClient()
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	_, err := client.Post("/orders", map[string]string{"side": "buy"}, &map[string]interface{}{})
+	if !IsServerError(err) {
+		t.Fatalf("Expected a server error, actual = %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("Expected POST not to be retried, actual calls = %v", calls)
+	}
+}
+
+type failingCredentialProvider struct {
+	calls int
+}
+
+func (p *failingCredentialProvider) Credentials() (Credentials, error) {
+	p.calls += 1
+	return Credentials{}, errors.New("credentials unavailable")
+}
+
+func Test_Client_RetryPolicy_finalErrors(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	calls := 0
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/time",
+		func(req *http.Request) (*http.Response, error) {
+			calls += 1
+			if calls == 1 {
+				return nil, errors.New("connection reset by peer")
+			}
+			return httpmock.NewStringResponse(200, `{ "iso": "2015-01-07T23:47:25.201Z", "epoch": 1420674445.201 }`), nil
+		},
+	)
+	client := NewMockClient()
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	// A network error is retried
+	if _, err := GetTime(client); nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("Expected the network error to be retried, actual calls = %v", calls)
+	}
+	// A credentials error is not
+	provider := &failingCredentialProvider{}
+	client.Credentials = provider
+	stats := &RequestStats{}
+	_, err := GetAccountsContext(WithRequestStats(context.Background(), stats), client)
+	if err == nil || err.Error() != "credentials unavailable" {
+		t.Fatalf("Expected the credentials error, actual = %v", err)
+	}
+	if stats.Attempts != 1 || provider.calls != 1 {
+		t.Fatalf("Expected 1 attempt, actual = %v attempts and %v credentials lookups", stats.Attempts, provider.calls)
+	}
+}
+
+func Test_RetryPolicy_backoff(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	expected := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+	for i, delay := range expected {
+		if actual := policy.backoff(i+1, nil); actual != delay {
+			t.Fatalf("Expected delay %v after attempt %d, actual = %v", delay, i+1, actual)
+		}
+	}
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		actual := policy.backoff(1, nil)
+		if actual < 50*time.Millisecond || actual > 150*time.Millisecond {
+			t.Fatalf("Expected jittered delay within 50ms..150ms, actual = %v", actual)
+		}
+	}
+	policy.MaxDelay = time.Minute
+	res := &http.Response{Header: http.Header{"Retry-After": []string{"7"}}}
+	if actual := policy.backoff(1, res); actual != 7*time.Second {
+		t.Fatalf("Expected Retry-After to be honored, actual = %v", actual)
+	}
+	res = &http.Response{Header: http.Header{"Retry-After": []string{"3600"}}}
+	if actual := policy.backoff(1, res); actual != time.Minute {
+		t.Fatalf("Expected Retry-After to be clamped to MaxDelay, actual = %v", actual)
+	}
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2015, 10, 21, 07, 28, 00, 0, time.UTC)
+	if delay, ok := parseRetryAfter("120", now); !ok || delay != 2*time.Minute {
+		t.Fatalf("Expected 2m, actual = %v %v", delay, ok)
+	}
+	if delay, ok := parseRetryAfter("Wed, 21 Oct 2015 07:28:30 GMT", now); !ok || delay != 30*time.Second {
+		t.Fatalf("Expected 30s, actual = %v %v", delay, ok)
+	}
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Fatalf("Expected an invalid value to be ignored")
+	}
+}
@@ -0,0 +1,112 @@
+package clients
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+/*
+	Doer executes a single HTTP request. *http.Client satisfies it, as does anything
+	wrapping one (instrumented clients, test doubles, ...).
+*/
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+/*
+	The doer used by a Client that has no HTTPClient of its own.
+
+	It leaves Transport unset so requests go through http.DefaultTransport, which keeps a
+	pool of idle keep-alive connections and honours the HTTP_PROXY / HTTPS_PROXY environment.
+	The connection pool is therefore shared by every default Client in the process.
+*/
+var DefaultHTTPClient Doer = &http.Client{
+	Timeout: 30 * time.Second,
+}
+
+/*
+	Settings for a dedicated HTTP client. Zero values fall back to the defaults below.
+*/
+type HTTPConfig struct {
+	// Overall time limit for a request, including reading the response body
+	Timeout time.Duration
+	// Time limit for establishing the TCP connection
+	DialTimeout time.Duration
+	// Interval between TCP keep-alive probes on open connections
+	KeepAlive time.Duration
+	// Time limit for the TLS handshake
+	TLSHandshakeTimeout time.Duration
+	// How long an idle connection stays in the pool before being closed
+	IdleConnTimeout time.Duration
+	// Maximum number of idle connections across all hosts
+	MaxIdleConns int
+	// Maximum number of idle connections kept per host
+	MaxIdleConnsPerHost int
+	// Proxy selection, defaults to http.ProxyFromEnvironment
+	Proxy func(*http.Request) (*url.URL, error)
+	// TLS settings, e.g. custom root CAs or client certificates
+	TLSConfig *tls.Config
+}
+
+const (
+	defaultHTTPTimeout             = 30 * time.Second
+	defaultHTTPDialTimeout         = 10 * time.Second
+	defaultHTTPKeepAlive           = 30 * time.Second
+	defaultHTTPTLSHandshakeTimeout = 10 * time.Second
+	defaultHTTPIdleConnTimeout     = 90 * time.Second
+	defaultHTTPMaxIdleConns        = 100
+	defaultHTTPMaxIdleConnsPerHost = 10
+)
+
+/*
+	Create an *http.Client with its own connection pool configured from config.
+
+	Every request made through the returned client reuses idle connections to the API
+	host, so frequent polling does not pay for a new TCP connection and TLS handshake each time.
+*/
+func NewHTTPClient(config HTTPConfig) *http.Client {
+	if config.Timeout == 0 {
+		config.Timeout = defaultHTTPTimeout
+	}
+	if config.DialTimeout == 0 {
+		config.DialTimeout = defaultHTTPDialTimeout
+	}
+	if config.KeepAlive == 0 {
+		config.KeepAlive = defaultHTTPKeepAlive
+	}
+	if config.TLSHandshakeTimeout == 0 {
+		config.TLSHandshakeTimeout = defaultHTTPTLSHandshakeTimeout
+	}
+	if config.IdleConnTimeout == 0 {
+		config.IdleConnTimeout = defaultHTTPIdleConnTimeout
+	}
+	if config.MaxIdleConns == 0 {
+		config.MaxIdleConns = defaultHTTPMaxIdleConns
+	}
+	if config.MaxIdleConnsPerHost == 0 {
+		config.MaxIdleConnsPerHost = defaultHTTPMaxIdleConnsPerHost
+	}
+	if config.Proxy == nil {
+		config.Proxy = http.ProxyFromEnvironment
+	}
+	dialer := &net.Dialer{
+		Timeout:   config.DialTimeout,
+		KeepAlive: config.KeepAlive,
+	}
+	transport := &http.Transport{
+		Proxy:               config.Proxy,
+		DialContext:         dialer.DialContext,
+		TLSClientConfig:     config.TLSConfig,
+		TLSHandshakeTimeout: config.TLSHandshakeTimeout,
+		IdleConnTimeout:     config.IdleConnTimeout,
+		MaxIdleConns:        config.MaxIdleConns,
+		MaxIdleConnsPerHost: config.MaxIdleConnsPerHost,
+	}
+	return &http.Client{
+		Timeout:   config.Timeout,
+		Transport: transport,
+	}
+}
@@ -0,0 +1,75 @@
+package clients
+
+import (
+	"gopkg.in/jarcoal/httpmock.v1"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type recordingDoer struct {
+	requests []*http.Request
+	doer     Doer
+}
+
+func (d *recordingDoer) Do(req *http.Request) (*http.Response, error) {
+	d.requests = append(d.requests, req)
+	return d.doer.Do(req)
+}
+
+func Test_Client_HTTPClient(t *testing.T) {
+	// Setup the mocks
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(
+		"GET",
+		"https://mock-api.gdax.com/time",
+		httpmock.NewStringResponder(
+			200,
+			`{ "iso": "2015-01-07T23:47:25.201Z", "epoch": 1420674445.201 }`,
+		),
+	)
+	doer := &recordingDoer{doer: DefaultHTTPClient}
+	client := NewMockClient()
+	client.HTTPClient = doer
+	_, err := GetTime(client)
+	if nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if len(doer.requests) != 1 {
+		t.Fatalf("Expected 1 request to go through the custom doer, actual = %v", len(doer.requests))
+	}
+	if doer.requests[0].URL.Path != "/time" {
+		t.Fatalf("Expected request path = /time, actual = %v", doer.requests[0].URL.Path)
+	}
+}
+
+func Test_NewHTTPClient(t *testing.T) {
+	proxy_url, _ := url.Parse("http://proxy.local:3128")
+	client := NewHTTPClient(HTTPConfig{
+		Timeout: 5 * time.Second,
+		Proxy:   http.ProxyURL(proxy_url),
+	})
+	if client.Timeout != 5*time.Second {
+		t.Fatalf("Expected timeout = 5s, actual = %v", client.Timeout)
+	}
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected an *http.Transport, actual = %T", client.Transport)
+	}
+	if transport.MaxIdleConnsPerHost != defaultHTTPMaxIdleConnsPerHost {
+		t.Fatalf("Expected MaxIdleConnsPerHost = %v, actual = %v", defaultHTTPMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	}
+	if transport.IdleConnTimeout != defaultHTTPIdleConnTimeout {
+		t.Fatalf("Expected IdleConnTimeout = %v, actual = %v", defaultHTTPIdleConnTimeout, transport.IdleConnTimeout)
+	}
+	req, _ := http.NewRequest("GET", "https://api.gdax.com/time", nil)
+	actual, err := transport.Proxy(req)
+	if nil != err {
+		t.Fatalf("Expected error to be nil, actual = %v", err)
+	}
+	if actual.String() != proxy_url.String() {
+		t.Fatalf("Expected proxy = %v, actual = %v", proxy_url, actual)
+	}
+}
@@ -21,12 +21,9 @@ func main() {
 	log.Printf("granularity = %v", *granularityPtr)
 	log.Printf("environment = %v", *environmentPtr)
 
-	var client *clients.Client = nil
-	switch *environmentPtr {
-	case "sandbox":
-		client = clients.NewSandboxClient()
-	case "production":
-		client = clients.NewProductionClient()
+	client, err := clients.NewClient(clients.WithEnvironment(*environmentPtr))
+	if nil != err {
+		log.Fatal(err)
 	}
 
 	var startTime, endTime *time.Time